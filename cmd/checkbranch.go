@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var checkBranchCmd = &cobra.Command{
+	Use:   "check-branch [name]",
+	Short: "Validate a branch name against the branch naming policy",
+	Long:  "Checks a branch name (the current branch by default) against the devgod.branch.* naming policy in git config.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.CheckBranchName(firstArg(args))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkBranchCmd)
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var doneCmd = &cobra.Command{
+	Use:   "done [branch]",
+	Short: "Clean up after a task's PR is merged",
+	Long:  "Checks that the task's PR is merged, switches to the base branch, fast-forwards it, deletes the local (and optionally remote) task branch and archives the task.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.Done(firstArg(args))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doneCmd)
+}
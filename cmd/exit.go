@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Exit codes, one per failure class, so scripts can react without parsing
+// messages.
+const (
+	exitError         = 1 // anything not covered below
+	exitUsage         = 2 // bad flags or arguments
+	exitMissingInput  = 3 // a prompt could not be answered: stdin is not a terminal
+	exitAI            = 4 // the model could not be reached or failed
+	exitGitHub        = 5 // gh is missing, not logged in, or GitHub refused
+	exitCommitBlocked = 6 // a git hook or commit signing stopped the commit
+)
+
+// usageError marks an error in how the command was invoked.
+type usageError struct {
+	error
+}
+
+func (e usageError) Unwrap() error { return e.error }
+
+// exitCode maps an error to its exit code.
+func exitCode(err error) int {
+	var (
+		usage   usageError
+		missing *ui.MissingInputError
+		aiErr   *ai.Error
+		ghErr   *gitflow.GitHubError
+		hookErr *gitflow.HookError
+		signErr *gitflow.SigningError
+	)
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.As(err, &missing):
+		return exitMissingInput
+	case errors.As(err, &aiErr):
+		return exitAI
+	case errors.As(err, &ghErr):
+		return exitGitHub
+	case errors.As(err, &hookErr), errors.As(err, &signErr):
+		return exitCommitBlocked
+	}
+	return exitError
+}
@@ -1,12 +1,26 @@
 package cmd
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/jeethsoni/devgod-cli/internal/gitflow"
 	"github.com/spf13/cobra"
 )
 
+var (
+	gitIssue     string
+	gitFromIssue string
+	gitWorktree  bool
+	gitBase      string
+	gitSplit     bool
+	gitAmend     bool
+	gitFixup     bool
+	gitCoAuthors bool
+	gitMessage   string
+	gitAll       bool
+)
+
 // gitCmd represents the git command
 var gitCmd = &cobra.Command{
 	Use:   "git [intent]",
@@ -14,19 +28,65 @@ var gitCmd = &cobra.Command{
 	Long:  "Generate branches and commits from simple English instructions.",
 	Args:  cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := gitflow.StartOptions{
+			IssueID:    gitIssue,
+			Worktree:   gitWorktree,
+			BaseBranch: gitBase,
+		}
+
+		finishing := gitSplit || gitAmend || gitFixup || gitCoAuthors || gitMessage != "" || gitAll
+		if finishing && (len(args) > 0 || gitFromIssue != "" || gitIssue != "") {
+			return usageError{fmt.Errorf("--split, --amend, --fixup, --co-authors, --message and --all apply when finishing a task; run `devgod git` without an intent")}
+		}
+
+		// Seed the task from a GitHub issue
+		if gitFromIssue != "" {
+			if len(args) > 0 {
+				return usageError{fmt.Errorf("--from-issue takes the intent from the issue; drop the intent argument, or use --issue with your own intent")}
+			}
+			return gitflow.StartTaskFromIssue(gitFromIssue, opts)
+		}
+
 		// Join all args to form intent
 		intent := strings.Join(args, " ")
 
 		if strings.TrimSpace(intent) == "" {
+			// Only an issue ID then seed the task from the ticket
+			if gitIssue != "" {
+				return gitflow.StartTaskFromTicket(gitIssue, opts)
+			}
+
 			// No intent then start finish mode
-			return gitflow.FinishTask()
+			return gitflow.FinishTask(gitflow.FinishOptions{
+				Split:     gitSplit,
+				Amend:     gitAmend,
+				Fixup:     gitFixup,
+				CoAuthors: gitCoAuthors,
+				Message:   gitMessage,
+				All:       gitAll,
+			})
 		}
 
 		// Intent given then start mode
-		return gitflow.StartTask(intent)
+		return gitflow.StartTask(intent, opts)
 	},
 }
 
 func init() {
+	gitCmd.Flags().StringVar(&gitIssue, "issue", "", "issue ID to reference in the branch, commits and PR (e.g. ABC-123 or 42)")
+	gitCmd.Flags().StringVar(&gitFromIssue, "from-issue", "", "start a task from a GitHub issue number")
+	gitCmd.Flags().BoolVar(&gitWorktree, "worktree", false, "create the task branch in its own worktree directory")
+	gitCmd.Flags().StringVar(&gitBase, "base", "", "branch to start the task from (default: git config devgod.base, then origin/HEAD)")
+	gitCmd.Flags().BoolVar(&gitSplit, "split", false, "when finishing, split the staged changes into several logical commits, by file or hunk")
+	gitCmd.Flags().BoolVar(&gitAmend, "amend", false, "fold staged changes into the last commit and regenerate its message")
+	gitCmd.Flags().BoolVar(&gitFixup, "fixup", false, "record staged changes as a fixup! commit for an earlier commit on the task")
+	gitCmd.Flags().BoolVar(&gitCoAuthors, "co-authors", false, "when finishing, pick co-authors for this task's commits (pick none to clear)")
+	gitCmd.Flags().StringVarP(&gitMessage, "message", "m", "", "when finishing, use this commit message instead of generating one")
+	gitCmd.Flags().BoolVar(&gitAll, "all", false, "when finishing, commit every change instead of asking which files")
+	gitCmd.MarkFlagsMutuallyExclusive("from-issue", "issue")
+	gitCmd.MarkFlagsMutuallyExclusive("split", "amend", "fixup")
+	gitCmd.MarkFlagsMutuallyExclusive("message", "split")
+	gitCmd.MarkFlagsMutuallyExclusive("message", "amend")
+	gitCmd.MarkFlagsMutuallyExclusive("message", "fixup")
 	rootCmd.AddCommand(gitCmd)
 }
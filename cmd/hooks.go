@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install git hooks so commits from any tool get devgod messages",
+	Long:  "Installs prepare-commit-msg and commit-msg hooks: commits started without a message (e.g. from an IDE) get an AI-generated one, and every message is checked against the commit format. Existing hooks are kept and run first.",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the devgod commit hooks in this repo",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.InstallHooks()
+	},
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the devgod commit hooks and restore any previous ones",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.UninstallHooks()
+	},
+}
+
+// hookCmd is what the installed hook scripts call; it is not meant to be
+// run by hand.
+var hookCmd = &cobra.Command{
+	Use:    "hook",
+	Hidden: true,
+}
+
+var hookPrepareCmd = &cobra.Command{
+	Use:           "prepare-commit-msg <file> [source] [sha]",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := ""
+		if len(args) > 1 {
+			source = args[1]
+		}
+		return gitflow.PrepareCommitMsgHook(args[0], source)
+	},
+}
+
+var hookCommitMsgCmd = &cobra.Command{
+	Use:           "commit-msg <file>",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.CommitMsgHook(args[0])
+	},
+}
+
+func init() {
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd)
+	hookCmd.AddCommand(hookPrepareCmd, hookCommitMsgCmd)
+	rootCmd.AddCommand(hooksCmd, hookCmd)
+}
@@ -5,15 +5,28 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	prBase      string
+	prReviewers []string
+	prTemplate  string
+)
+
 var prCmd = &cobra.Command{
 	Use:   "pr",
 	Short: "Create a pull request for the current branch",
 	Long:  "Creates a pull request on the remote repository for the current branch using AI-generated title and description.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return gitflow.CreatePR()
+		return gitflow.CreatePR(gitflow.PROptions{
+			Base:      prBase,
+			Reviewers: prReviewers,
+			Template:  prTemplate,
+		})
 	},
 }
 
 func init() {
+	prCmd.Flags().StringVar(&prBase, "base", "", "branch to open the PR against (default: the task's base, after asking)")
+	prCmd.Flags().StringSliceVar(&prReviewers, "reviewer", nil, "GitHub login to request a review from (repeatable or comma-separated)")
+	prCmd.Flags().StringVar(&prTemplate, "template", "", "PR template to fill, relative to the repo root, or \"none\"")
 	rootCmd.AddCommand(prCmd)
 }
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var releaseBump string
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Tag the next semantic version from conventional commits",
+	Long:  "Parses commits since the latest semver tag, proposes the next major/minor/patch version, creates an annotated tag with generated notes and optionally publishes a GitHub release.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.Release(releaseBump)
+	},
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&releaseBump, "bump", "", "override the suggested bump (major, minor or patch)")
+	rootCmd.AddCommand(releaseCmd)
+}
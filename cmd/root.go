@@ -4,9 +4,19 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	assumeYes bool
+	dryRun    bool
+
+	// cleanupDryRun removes the scratch index a dry run stages into.
+	cleanupDryRun = func() {}
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "devgod-cli",
@@ -15,12 +25,45 @@ var rootCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("devgod-cli: try `devgod git 'your task'`")
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		ui.SetAssumeYes(assumeYes)
+		if dryRun {
+			cleanup, err := gitflow.EnableDryRun()
+			if err != nil {
+				return err
+			}
+			cleanupDryRun = cleanup
+		}
+
+		// Keep script logs to the one error line Execute prints
+		if !ui.Interactive() {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	cleanupDryRun()
+
+	// A prompt skipped for lack of a terminal means the run did not do
+	// what was asked, even when the command itself carried on
+	if err == nil && len(ui.Unanswered()) > 0 {
+		err = &ui.MissingInputError{Inputs: ui.Unanswered()}
+	}
+	if err != nil {
 		fmt.Println("Error:", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show the git, gh and state changes a command would make without making them")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer yes to confirmations and skip optional questions (for scripts and CI)")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	squashBase string
+	squashUndo bool
+)
+
+var squashCmd = &cobra.Command{
+	Use:   "squash",
+	Short: "Squash the task branch into a single commit",
+	Long:  "Squashes every commit since the task branched off into one commit with an AI-written message. The old tip is kept as a backup so the squash can be undone with --undo.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if squashUndo {
+			return gitflow.SquashUndo()
+		}
+		return gitflow.Squash(squashBase)
+	},
+}
+
+func init() {
+	squashCmd.Flags().StringVar(&squashBase, "base", "", "branch the task started from (default: the task's base)")
+	squashCmd.Flags().BoolVar(&squashUndo, "undo", false, "restore the branch as it was before the last squash")
+	rootCmd.AddCommand(squashCmd)
+}
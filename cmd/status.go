@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the current task, branch, push and PR status",
+	Long:  "Shows the current task, commits ahead/behind its base branch, uncommitted changes, push state and PR/CI status in one screen.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.Status()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncRebase   bool
+	syncMerge    bool
+	syncContinue bool
+	syncAbort    bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Bring the task branch up to date with its base",
+	Long:  "Fetches the task's base branch and rebases (or merges) the task branch onto it, walking you through any conflicts, then offers to push. The default strategy comes from `git config devgod.sync.strategy` (rebase or merge).",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := gitflow.SyncOptions{
+			Continue: syncContinue,
+			Abort:    syncAbort,
+		}
+		switch {
+		case syncRebase:
+			opts.Strategy = "rebase"
+		case syncMerge:
+			opts.Strategy = "merge"
+		}
+		return gitflow.Sync(opts)
+	},
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncRebase, "rebase", false, "rebase onto the base branch")
+	syncCmd.Flags().BoolVar(&syncMerge, "merge", false, "merge the base branch in")
+	syncCmd.Flags().BoolVar(&syncContinue, "continue", false, "continue a sync that stopped on conflicts")
+	syncCmd.Flags().BoolVar(&syncAbort, "abort", false, "abort a sync that stopped on conflicts")
+	syncCmd.MarkFlagsMutuallyExclusive("rebase", "merge", "continue", "abort")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/jeethsoni/devgod-cli/internal/gitflow"
+	"github.com/spf13/cobra"
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Manage the devgod tasks in this repo",
+	Long:  "List, switch between, drop and rename the tasks devgod is tracking, so several bugfixes and features can be in flight at once.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.ListTasks()
+	},
+}
+
+var tasksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all tasks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.ListTasks()
+	},
+}
+
+var tasksSwitchCmd = &cobra.Command{
+	Use:   "switch [branch]",
+	Short: "Check out the branch of another task",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.SwitchTask(firstArg(args))
+	},
+}
+
+var tasksDropCmd = &cobra.Command{
+	Use:   "drop [branch]",
+	Short: "Stop tracking a task (the branch is kept)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return gitflow.DropTask(firstArg(args))
+	},
+}
+
+var tasksRenameCmd = &cobra.Command{
+	Use:   "rename [branch] <new-branch>",
+	Short: "Rename a task's branch",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return gitflow.RenameTask("", args[0])
+		}
+		return gitflow.RenameTask(args[0], args[1])
+	},
+}
+
+// firstArg returns args[0], or "" when no args were given.
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksListCmd, tasksSwitchCmd, tasksDropCmd, tasksRenameCmd)
+	rootCmd.AddCommand(tasksCmd)
+}
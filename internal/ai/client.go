@@ -26,8 +26,25 @@ type ollamaChatResponse struct {
 	} `json:"message"`
 }
 
+// Error reports a failure to get an answer from the model, so callers can
+// tell AI problems apart from git or GitHub ones.
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
 // Chat sends a prompt to Ollama and returns the response text.
 func Chat(model string, systemPrompt string, userPrompt string) (string, error) {
+	out, err := chat(model, systemPrompt, userPrompt)
+	if err != nil {
+		return "", &Error{Err: err}
+	}
+	return out, nil
+}
+
+func chat(model string, systemPrompt string, userPrompt string) (string, error) {
 	reqBody := ollamaChatRequest{
 		Model: model,
 		Messages: []ollamaMessage{
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConflictHunk is one conflicted region of a file, with the two sides, the
+// common ancestor when known, and a few lines around it.
+type ConflictHunk struct {
+	Path string
+
+	// Mine is the task branch's side, Upstream the base branch's side.
+	Mine     string
+	Upstream string
+	// Base is the common ancestor; empty when git did not record it.
+	Base string
+
+	Before string
+	After  string
+}
+
+// SuggestConflictResolution asks the model to merge both sides of a
+// conflict hunk. It returns only the text that should replace the hunk.
+func SuggestConflictResolution(h ConflictHunk) (string, error) {
+	systemPrompt := `
+You are a senior engineer resolving a git merge conflict in ONE region of a file.
+
+You will receive:
+- The file path (for language and conventions)
+- Code just BEFORE and AFTER the conflict (context only; never repeat it)
+- MINE: the version from the developer's task branch
+- UPSTREAM: the version from the base branch
+- BASE: the common ancestor both sides started from (may be missing)
+
+Your job is to output the code that should REPLACE the conflicted region.
+
+RULES (NO EXCEPTIONS):
+- Output ONLY the replacement code. No explanations, no markdown, no code fences.
+- NEVER output conflict markers (<<<<<<<, =======, >>>>>>>, |||||||).
+- NEVER repeat the BEFORE or AFTER context.
+- Keep the intent of BOTH sides: compare each side to BASE to see what it changed, and apply both changes.
+- If both sides changed the same thing differently, prefer UPSTREAM's structure and re-apply MINE's change on top of it.
+- Keep the file's indentation style exactly.
+- If one side only deleted code the other side did not touch, keep the deletion.`
+
+	base := h.Base
+	if strings.TrimSpace(base) == "" {
+		base = "(not available)"
+	}
+
+	userPrompt := fmt.Sprintf(`FILE: %s
+
+BEFORE:
+%s
+MINE:
+%s
+UPSTREAM:
+%s
+BASE:
+%s
+AFTER:
+%s`, h.Path, h.Before, h.Mine, h.Upstream, base, h.After)
+
+	raw, err := Chat(DefaultModel, systemPrompt, userPrompt)
+	if err != nil {
+		return "", fmt.Errorf("AI conflict resolution failed: %w", err)
+	}
+
+	// Drop code fences but keep indentation, which stripCodeFences trims
+	lines := strings.Split(strings.Trim(raw, "\r\n"), "\n")
+	if len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "```") {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[len(lines)-1]), "```") {
+		lines = lines[:len(lines)-1]
+	}
+	out := strings.Join(lines, "\n")
+
+	for _, marker := range []string{"<<<<<<<", "=======", ">>>>>>>", "|||||||"} {
+		if strings.Contains(out, marker) {
+			return "", fmt.Errorf("model returned conflict markers instead of a resolution")
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,206 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	templateHeadingRe  = regexp.MustCompile(`^#{1,6}\s+\S`)
+	templateCheckboxRe = regexp.MustCompile(`^\s*[-*+]\s+\[[ xX]\]`)
+	templateCommentRe  = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// TemplateSection is one heading of a PR template together with the
+// guidance text and checkboxes that follow it.
+type TemplateSection struct {
+	Heading    string   // verbatim heading line, empty for text before the first heading
+	Guidance   string   // free text / HTML comments telling the author what to write
+	Checkboxes []string // verbatim "- [ ] ..." lines
+}
+
+// ParsePRTemplate splits a markdown PR template into its sections.
+func ParsePRTemplate(template string) []TemplateSection {
+	var sections []TemplateSection
+	current := TemplateSection{}
+	var guidance []string
+
+	flush := func() {
+		current.Guidance = strings.TrimSpace(strings.Join(guidance, "\n"))
+		if current.Heading != "" || current.Guidance != "" || len(current.Checkboxes) > 0 {
+			sections = append(sections, current)
+		}
+		current = TemplateSection{}
+		guidance = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(template, "\r\n", "\n"), "\n") {
+		switch {
+		case templateHeadingRe.MatchString(line):
+			flush()
+			current.Heading = strings.TrimRight(line, " \t")
+		case templateCheckboxRe.MatchString(line):
+			current.Checkboxes = append(current.Checkboxes, strings.TrimRight(line, " \t"))
+		default:
+			guidance = append(guidance, line)
+		}
+	}
+	flush()
+
+	return sections
+}
+
+// assemblePRBody rebuilds the PR body from the template sections and the
+// model-written content, keeping headings and checkboxes verbatim.
+func assemblePRBody(sections []TemplateSection, contents []string) string {
+	var parts []string
+	for i, s := range sections {
+		content := ""
+		if i < len(contents) {
+			content = contents[i]
+		}
+
+		// Drop any checkbox lines the model echoed; the template's own lines win.
+		var kept []string
+		for _, line := range strings.Split(content, "\n") {
+			if templateCheckboxRe.MatchString(line) || (s.Heading != "" && strings.TrimSpace(line) == s.Heading) {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		content = strings.TrimSpace(strings.Join(kept, "\n"))
+
+		var block []string
+		if s.Heading != "" {
+			block = append(block, s.Heading)
+		}
+		if content == "" && len(s.Checkboxes) == 0 && s.Heading != "" {
+			content = "N/A"
+		}
+		if content != "" {
+			block = append(block, content)
+		}
+		if len(s.Checkboxes) > 0 {
+			block = append(block, strings.Join(s.Checkboxes, "\n"))
+		}
+		if len(block) > 0 {
+			parts = append(parts, strings.Join(block, "\n\n"))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+type templateFillResponse struct {
+	Title    string   `json:"title"`
+	Sections []string `json:"sections"`
+}
+
+// GeneratePRFromTemplate asks the model to fill each section of the given PR
+// template from the diff and intent. Headings and checkboxes are copied from
+// the template verbatim; only the prose under each heading is generated.
+func GeneratePRFromTemplate(intent, diff, branch, baseBranch, template string) (*PRMetadata, error) {
+	sections := ParsePRTemplate(template)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("PR template is empty")
+	}
+
+	systemPrompt := `
+You are a senior software engineer filling in a GitHub Pull Request template.
+
+You will receive:
+- A high-level task intent (for wording only)
+- A git diff or diff summary (THIS IS THE ONLY SOURCE OF TRUTH)
+- A numbered list of template sections, each with a heading and the author guidance for it
+
+Your job is to output ONE JSON OBJECT:
+
+{
+  "title": "<short-title>",
+  "sections": ["<content for section 1>", "<content for section 2>", ...]
+}
+
+========================
+STRICT RULES (NO EXCEPTIONS)
+========================
+
+JSON RULES:
+- Output MUST be valid JSON.
+- No text before or after the JSON.
+- No code fences.
+- Only "title" and "sections" keys are allowed.
+- "sections" MUST contain exactly one string per template section, in the same order.
+
+TITLE RULES:
+- 3–9 words.
+- One line only.
+- No quotes, no backticks, no emojis, no brackets.
+- Must summarize the purpose of the PR.
+
+SECTION RULES:
+- Each string is the markdown content that goes UNDER that section's heading.
+- Do NOT repeat the heading.
+- Do NOT include checkboxes; they are added automatically.
+- Follow the section guidance, but never copy HTML comments.
+- Keep each section short: 1–4 sentences or a short bullet list.
+- If the diff gives nothing for a section, write "N/A".
+
+DIFF-ONLY TRUTH RULE:
+- Only describe changes visible in the diff.
+- Do NOT invent tests, error handling, validation, performance improvements, or any behavior not present.`
+
+	var sb strings.Builder
+	for i, s := range sections {
+		heading := s.Heading
+		if heading == "" {
+			heading = "(text before the first heading)"
+		}
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, heading)
+		if s.Guidance != "" {
+			fmt.Fprintf(&sb, "   guidance: %s\n", strings.ReplaceAll(s.Guidance, "\n", " "))
+		}
+	}
+
+	userPrompt := fmt.Sprintf(`
+Task intent:
+%s
+
+Branch: %s
+Base branch: %s
+
+TEMPLATE SECTIONS (%d):
+%s
+RAW GIT DIFF:
+%s
+`, strings.TrimSpace(intent), branch, baseBranch, len(sections), sb.String(), strings.TrimSpace(diff))
+
+	raw, err := Chat(prModel, systemPrompt, userPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("AI PR template generation failed: %w", err)
+	}
+
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "```") {
+		raw = stripCodeFences(raw)
+	}
+
+	resp := &templateFillResponse{}
+	if err := json.Unmarshal([]byte(raw), resp); err != nil {
+		return nil, fmt.Errorf("failed to parse AI JSON: %w\nraw output:\n%s", err, raw)
+	}
+
+	if strings.TrimSpace(resp.Title) == "" {
+		return nil, fmt.Errorf("AI returned incomplete PR metadata:\n%s", raw)
+	}
+
+	// Never let guidance comments leak into the rendered body.
+	for i := range resp.Sections {
+		resp.Sections[i] = templateCommentRe.ReplaceAllString(resp.Sections[i], "")
+	}
+
+	return &PRMetadata{
+		Title: strings.TrimSpace(resp.Title),
+		Body:  assemblePRBody(sections, resp.Sections),
+	}, nil
+}
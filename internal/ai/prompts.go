@@ -2,24 +2,49 @@ package ai
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
 const DefaultModel = "llama3.1"
 
-// GenerateBranchName uses AI to create a clean git branch name.
-func GenerateBranchName(intent string) (string, error) {
+// DefaultBranchTypes are the branch types allowed when no policy overrides them.
+var DefaultBranchTypes = []string{"feat", "fix", "chore", "refactor", "docs", "style", "test"}
+
+// BranchNameRules tunes GenerateBranchName to a team's naming policy.
+type BranchNameRules struct {
+	// Types allowed as <type>; DefaultBranchTypes when empty.
+	Types []string
+	// MaxLength caps the <type>/<slug> length; 0 means no limit.
+	MaxLength int
+}
+
+// GenerateBranchName uses AI to create a clean git branch name of the form
+// <type>/<slug>. issueID may be empty; when set it is appended to the end
+// of the slug.
+func GenerateBranchName(intent, issueID string, rules BranchNameRules) (string, error) {
+	types := rules.Types
+	if len(types) == 0 {
+		types = DefaultBranchTypes
+	}
+	typeList := strings.Join(types, ", ")
+
+	lengthRule := "- Keep the branch reasonably short (~40 characters if possible)."
+	if rules.MaxLength > 0 {
+		lengthRule = fmt.Sprintf("- The whole branch name MUST be at most %d characters.", rules.MaxLength)
+	}
+
 	systemPrompt := `You are a senior engineer generating git branch names.
 
 You MUST follow these rules:
 
 - Your ONLY output must be a valid git branch name.
 - The format MUST be: <type>/<slug>
-- <type> MUST be one of: feat, fix, chore, refactor, docs, style, test.
+- <type> MUST be one of: {{TYPES}}.
 - <slug> MUST be 2–6 meaningful words about the task, in lowercase kebab-case.
 - Use hyphens (-) between all words in the slug.
-- Keep the branch reasonably short (~40 characters if possible).
-- NEVER output only the type (feat, fix, chore, refactor, docs, style, test).
+{{LENGTH_RULE}}
+- NEVER output only the type ({{TYPES}}).
 - NEVER include explanations, quotes, or any other text besides the branch name.
 
 ISSUE ID RULES:
@@ -56,7 +81,15 @@ fix/empty-password-login-crash
 or:
 fix/empty-password-login-crash-BUG-21`
 
-	userPrompt := fmt.Sprintf("intent: %s", strings.TrimSpace(intent))
+	systemPrompt = strings.ReplaceAll(systemPrompt, "{{TYPES}}", typeList)
+	systemPrompt = strings.ReplaceAll(systemPrompt, "{{LENGTH_RULE}}", lengthRule)
+
+	issueID = strings.TrimSpace(issueID)
+	issueField := issueID
+	if issueField == "" {
+		issueField = "none"
+	}
+	userPrompt := fmt.Sprintf("intent: %s\nissue id: %s", strings.TrimSpace(intent), issueField)
 
 	if intent == "" {
 		return "", fmt.Errorf("intent cannot be empty")
@@ -88,14 +121,29 @@ fix/empty-password-login-crash-BUG-21`
 	}
 
 	prefix := strings.SplitN(branch, "/", 2)[0]
-	if prefix != "feat" && prefix != "fix" && prefix != "chore" &&
-		prefix != "refactor" && prefix != "docs" && prefix != "style" && prefix != "test" {
+	if !slices.Contains(types, prefix) {
 		return "", fmt.Errorf("model returned invalid prefix in branch name: %q", branch)
 	}
 
+	// The model sometimes drops the issue ID; make sure it ends up in the name.
+	if issueID != "" && !strings.Contains(strings.ToLower(branch), strings.ToLower(issueID)) {
+		branch = strings.TrimRight(branch, "-") + "-" + issueID
+	}
+
 	return branch, nil
 }
 
+// warningPrefix starts the line the commit message prompt tells the model
+// to output instead of a message when the diff holds secrets or large
+// binaries (see SAFETY RULES below).
+const warningPrefix = "WARNING:"
+
+// IsWarning reports whether a message from GenerateCommitMessage is the
+// model's safety warning rather than a commit message.
+func IsWarning(msg string) bool {
+	return strings.HasPrefix(strings.TrimSpace(msg), warningPrefix)
+}
+
 // GenerateCommitMessage uses AI to generate a single-line commit message.
 // Priority: summary -> diff -> intent.
 func GenerateCommitMessage(intent, summary, diff string) (string, error) {
@@ -181,3 +229,52 @@ TASK INTENT (SECONDARY):
 	msg = strings.Split(msg, "\n")[0]
 	return msg, nil
 }
+
+// GenerateSquashMessage uses AI to write the subject line for a commit that
+// replaces a whole task branch. subjects are the subjects of the commits
+// being squashed, oldest first.
+func GenerateSquashMessage(intent, summary, diff string, subjects []string) (string, error) {
+	const squashPrompt = `
+You are writing the subject line for ONE git commit that replaces a series of commits on a feature branch.
+
+SOURCES (IN ORDER):
+1) COMBINED DIFF SUMMARY and DIFF (what the branch changes overall)
+2) ORIGINAL COMMIT SUBJECTS (how the author described the steps)
+3) TASK INTENT (wording help only)
+
+HARD RULES (NO EXCEPTIONS):
+- Output MUST be EXACTLY ONE LINE: "<type>: <short description>"
+- <type> MUST be one of: feat, fix, chore, refactor, docs, style, test
+- Pick the type that describes the branch as a whole, not its first or last step.
+- <short description> MUST be 3–10 words, total length <= 60 characters.
+- Imperative mood. No body. No markdown. No quotes. No emojis.
+- Ignore "fixup!", "wip" and typo-fix steps; describe the final outcome.
+`
+
+	userPrompt := fmt.Sprintf(`COMBINED SUMMARY:
+%s
+
+ORIGINAL COMMIT SUBJECTS:
+- %s
+
+COMBINED DIFF:
+%s
+
+TASK INTENT:
+%s
+`,
+		strings.TrimSpace(summary),
+		strings.Join(subjects, "\n- "),
+		strings.TrimSpace(diff),
+		strings.TrimSpace(intent),
+	)
+
+	raw, err := Chat(DefaultModel, squashPrompt, userPrompt)
+	if err != nil {
+		return "", err
+	}
+
+	msg := strings.TrimSpace(raw)
+	msg = strings.Split(msg, "\n")[0]
+	return msg, nil
+}
@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CommitGroup is one proposed commit in a split: its message and the files
+// it contains, where "path#N" stands for hunk N of a file cut into hunks.
+type CommitGroup struct {
+	Message string   `json:"message"`
+	Files   []string `json:"files"`
+}
+
+type splitResponse struct {
+	Commits []CommitGroup `json:"commits"`
+}
+
+// GenerateCommitSplit asks the model to split the staged changes into a
+// sequence of small, coherent commits. summary is the name-status list of
+// staged files, hunks lists the "path#N" hunks of files that may be split
+// across commits, and hint is a directory-based grouping the model may
+// refine. The result is not validated against the staged files; callers
+// must do so.
+func GenerateCommitSplit(intent, summary, hunks, diff string, hint [][]string) ([]CommitGroup, error) {
+	systemPrompt := `
+You are a senior engineer splitting a large set of staged changes into several logical git commits.
+
+You will receive:
+- The task intent (for wording only)
+- The staged files in name-status form (A/M/D)
+- The hunks of files that may be split, named "path#N"
+- A suggested grouping by directory (a starting point, NOT a rule)
+- The staged diff or a summary of it
+
+Your job is to output ONE JSON OBJECT:
+
+{
+  "commits": [
+    {"message": "<type>: <short description>", "files": ["path/a.go", "path/b.go#1"]},
+    ...
+  ]
+}
+
+========================
+STRICT RULES (NO EXCEPTIONS)
+========================
+
+JSON RULES:
+- Output MUST be valid JSON.
+- No text before or after the JSON.
+- No code fences.
+
+GROUPING RULES:
+- Every staged file MUST appear in exactly one commit.
+- Use the exact paths you were given; never invent paths.
+- A file listed under HUNKS may be split: put "path#N" in a commit to place hunk N there. Use the plain path to keep the whole file together. Never list a file both ways.
+- Group by purpose: a refactor, a fix and a feature belong in separate commits.
+- Keep a deleted file and the file that replaces it in the same commit.
+- Order commits so each one builds on the previous (refactors and chores first, then fixes, then features).
+- Prefer 2–5 commits. Do not create a commit per file unless the files are unrelated.
+
+MESSAGE RULES:
+- Each message MUST be ONE LINE: "<type>: <short description>".
+- <type> MUST be one of: feat, fix, chore, refactor, docs, style, test
+- 3–10 words, at most 60 characters, imperative mood.
+- Only describe changes visible in that commit's files.`
+
+	var sb strings.Builder
+	for i, group := range hint {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, strings.Join(group, ", "))
+	}
+
+	hunkList := strings.TrimSpace(hunks)
+	if hunkList == "" {
+		hunkList = "(none; split whole files)"
+	}
+
+	userPrompt := fmt.Sprintf(`
+Task intent:
+%s
+
+STAGED FILES (name-status):
+%s
+
+HUNKS:
+%s
+
+SUGGESTED GROUPING BY DIRECTORY:
+%s
+STAGED DIFF:
+%s
+`, strings.TrimSpace(intent), strings.TrimSpace(summary), hunkList, sb.String(), strings.TrimSpace(diff))
+
+	raw, err := Chat(DefaultModel, systemPrompt, userPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("AI commit split failed: %w", err)
+	}
+
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "```") {
+		raw = stripCodeFences(raw)
+	}
+
+	resp := &splitResponse{}
+	if err := json.Unmarshal([]byte(raw), resp); err != nil {
+		return nil, fmt.Errorf("failed to parse AI JSON: %w\nraw output:\n%s", err, raw)
+	}
+	if len(resp.Commits) == 0 {
+		return nil, fmt.Errorf("AI returned no commits:\n%s", raw)
+	}
+
+	for i := range resp.Commits {
+		msg := strings.TrimSpace(resp.Commits[i].Message)
+		resp.Commits[i].Message = strings.Split(msg, "\n")[0]
+	}
+	return resp.Commits, nil
+}
@@ -0,0 +1,47 @@
+package gitflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// resolveBaseBranch picks the branch new tasks start from:
+// the --base flag, then `git config devgod.base`, then origin/HEAD.
+// Returns "" when none of them is available.
+func resolveBaseBranch(flag string) string {
+	if b := strings.TrimSpace(flag); b != "" {
+		return strings.TrimPrefix(b, "origin/")
+	}
+	if b := configValue("base"); b != "" {
+		return strings.TrimPrefix(b, "origin/")
+	}
+	return DefaultRemoteBranch()
+}
+
+// freshStartPoint fetches base from origin and returns the ref a new task
+// branch should start from. It falls back to the local base branch, and
+// finally to HEAD (""), when the remote is unavailable.
+func freshStartPoint(base string) string {
+	if base == "" {
+		return ""
+	}
+
+	if HasRemote("origin") {
+		stop := ui.StartSpinner(fmt.Sprintf("Fetching latest %s from origin...", base))
+		err := FetchOrigin(base)
+		stop()
+		if err == nil && RefExists("refs/remotes/origin/"+base) {
+			return "origin/" + base
+		}
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ Could not fetch %s from origin; using the local copy.", base)))
+	}
+
+	if RefExists("refs/heads/" + base) {
+		return base
+	}
+
+	fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ Base branch %s not found; branching from the current HEAD.", base)))
+	return ""
+}
@@ -0,0 +1,178 @@
+package gitflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// What to do when the proposed branch name already exists.
+const (
+	collisionAdopt      = "Check out the existing branch and use it for this task"
+	collisionSuffix     = "Use %s instead"
+	collisionRegenerate = "Ask for a different name"
+	collisionCancel     = "Cancel"
+)
+
+// branchTaken reports whether name exists as a local branch or on origin.
+func branchTaken(name string) (local, remote bool) {
+	local = RefExists("refs/heads/" + name)
+	remote = HasRemote("origin") && IsBranchPushed(name)
+	return local, remote
+}
+
+// nextFreeBranchName appends the first numeric suffix (-2, -3, ...) that
+// is not taken locally or on origin.
+func nextFreeBranchName(name string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if local, remote := branchTaken(candidate); !local && !remote {
+			return candidate
+		}
+	}
+}
+
+// CheckoutRemoteBranch creates a local branch tracking origin/<name>.
+func CheckoutRemoteBranch(name string) error {
+	_, err := mutate("git", "checkout", "--track", "origin/"+name)
+	return err
+}
+
+// AddWorktreeForBranch checks out an existing branch in a new worktree.
+// When the branch only exists on origin a tracking branch is created.
+func AddWorktreeForBranch(branch, dir string, remoteOnly bool) error {
+	args := []string{"worktree", "add", dir, branch}
+	if remoteOnly {
+		args = []string{"worktree", "add", "--track", "-b", branch, dir, "origin/" + branch}
+	}
+	_, err := mutate("git", args...)
+	return err
+}
+
+// generateBranchName asks the model for a branch name, forcing the branch
+// type when one is given, steering away from names already rejected and
+// shaping the result with the branch policy.
+func generateBranchName(intent, issueID, branchType string, policy BranchPolicy, avoid []string) (string, error) {
+	prompt := intent
+	if len(avoid) > 0 {
+		prompt = fmt.Sprintf("%s\n(these branch names are already taken, pick a different one: %s)", intent, strings.Join(avoid, ", "))
+	}
+
+	stop := ui.StartSpinner("🪄 Asking the dev gods for the perfect branch name...")
+	name, err := ai.GenerateBranchName(prompt, branchIssueID(issueID), policy.AIRules())
+	stop()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate branch name: %w", err)
+	}
+
+	if branchType != "" {
+		name = branchType + "/" + strings.SplitN(name, "/", 2)[1]
+	}
+	return policy.Render(name, issueID), nil
+}
+
+// askBranchName lets the user type a branch name. Returns "" to cancel.
+func askBranchName() (string, error) {
+	return ui.Input(ui.Cyan("Type a branch name instead (blank to cancel):"))
+}
+
+// branchChoice is the outcome of chooseBranchName.
+type branchChoice struct {
+	Name string
+	// Adopt is true when Name already exists and should be checked out
+	// rather than created. RemoteOnly is set when it only exists on origin.
+	Adopt      bool
+	RemoteOnly bool
+}
+
+// chooseBranchName generates a branch name, checks it against the branch
+// policy, resolves collisions with existing local or remote branches, and
+// asks the user to confirm. Returns (nil, nil) when the user cancels.
+func chooseBranchName(intent, issueID string, opts StartOptions) (*branchChoice, error) {
+	policy := LoadBranchPolicy()
+	if policy.RequireIssue && issueID == "" {
+		return nil, fmt.Errorf("the branch policy requires a ticket ID; pass one with --issue")
+	}
+	if opts.BranchType != "" && !slices.Contains(policy.Types, opts.BranchType) {
+		opts.BranchType = ""
+	}
+
+	var rejected []string
+
+	name, err := generateBranchName(intent, issueID, opts.BranchType, policy, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := policy.Validate(name); err != nil {
+			fmt.Println(ui.Yellow("⚠️ " + err.Error()))
+			if name, err = askBranchName(); err != nil || name == "" {
+				return nil, err
+			}
+			continue
+		}
+
+		local, remote := branchTaken(name)
+		if local || remote {
+			where := "locally"
+			switch {
+			case local && remote:
+				where = "locally and on origin"
+			case remote:
+				where = "on origin"
+			}
+
+			fmt.Println()
+			fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ Branch %q already exists %s.", name, where)))
+
+			suffixed := nextFreeBranchName(name)
+			if ui.AssumeYes() {
+				fmt.Println(ui.Dim(fmt.Sprintf("Using %s instead (--yes).", suffixed)))
+				name = suffixed
+				continue
+			}
+			options := []string{
+				collisionAdopt,
+				fmt.Sprintf(collisionSuffix, suffixed),
+				collisionRegenerate,
+				collisionCancel,
+			}
+			for i, o := range options {
+				fmt.Printf("  %2d) %s\n", i+1, o)
+			}
+			fmt.Println()
+
+			choice, err := ui.SelectOne(options, ui.Cyan("What would you like to do?"))
+			if err != nil {
+				return nil, err
+			}
+
+			switch choice {
+			case collisionAdopt:
+				return &branchChoice{Name: name, Adopt: true, RemoteOnly: !local}, nil
+			case options[1]:
+				name = suffixed
+			case collisionRegenerate:
+				rejected = append(rejected, name)
+				name, err = generateBranchName(intent, issueID, opts.BranchType, policy, rejected)
+				if err != nil {
+					return nil, err
+				}
+			default:
+				return nil, nil
+			}
+			continue
+		}
+
+		if ui.Confirm(fmt.Sprintf("Use branch name \"%s\"?", name)) {
+			return &branchChoice{Name: name}, nil
+		}
+		if name, err = askBranchName(); err != nil || name == "" {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package gitflow
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Lowercase modes for branch names.
+const (
+	LowercaseSlug = "slug" // slug lowercase, issue IDs keep their case (default)
+	LowercaseAll  = "all"  // the whole branch name is lowercase
+	LowercaseNone = "none" // no case rules
+)
+
+const defaultBranchPattern = "{type}/{slug}"
+
+var (
+	nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+	placeholder  = regexp.MustCompile(`\{(user|type|slug|issue)\}`)
+)
+
+// BranchPolicy describes how task branches must be named. It is read from
+// git config so a team can commit it to a shared config or set it globally:
+//
+//	devgod.branch.pattern       e.g. "{user}/{type}/{slug}" (default "{type}/{slug}")
+//	devgod.branch.types         e.g. "feat,fix,perf,ci"
+//	devgod.branch.maxLength     e.g. 50 (0 = no limit)
+//	devgod.branch.lowercase     slug | all | none
+//	devgod.branch.requireIssue  true | false
+//	devgod.user                 value for {user} (default: local part of user.email)
+type BranchPolicy struct {
+	Pattern      string
+	Types        []string
+	MaxLength    int
+	Lowercase    string
+	RequireIssue bool
+	User         string
+}
+
+// LoadBranchPolicy reads the branch policy from git config, filling in
+// defaults for anything unset.
+func LoadBranchPolicy() BranchPolicy {
+	p := BranchPolicy{
+		Pattern:   defaultBranchPattern,
+		Types:     ai.DefaultBranchTypes,
+		Lowercase: LowercaseSlug,
+	}
+
+	if v := configValue("branch.pattern"); v != "" {
+		p.Pattern = v
+	}
+	if v := configValue("branch.types"); v != "" {
+		if types := splitList(v); len(types) > 0 {
+			p.Types = types
+		}
+	}
+	if v := configValue("branch.maxlength"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			p.MaxLength = n
+		}
+	}
+	switch v := strings.ToLower(configValue("branch.lowercase")); v {
+	case LowercaseAll, LowercaseNone, LowercaseSlug:
+		p.Lowercase = v
+	case "true":
+		p.Lowercase = LowercaseAll
+	case "false":
+		p.Lowercase = LowercaseNone
+	}
+	p.RequireIssue, _ = strconv.ParseBool(configValue("branch.requireissue"))
+
+	if strings.Contains(p.Pattern, "{user}") {
+		p.User = branchUser()
+	}
+	return p
+}
+
+// branchUser returns the {user} value: devgod.user, or the local part of
+// user.email, slugified.
+func branchUser() string {
+	user := configValue("user")
+	if user == "" {
+		if out, err := shell.Run("git", "config", "--get", "user.email"); err == nil {
+			user = strings.SplitN(strings.TrimSpace(out), "@", 2)[0]
+		}
+	}
+	return slugify(user)
+}
+
+func slugify(s string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// AIRules converts the policy into the constraints given to the model.
+func (p BranchPolicy) AIRules() ai.BranchNameRules {
+	return ai.BranchNameRules{Types: p.Types, MaxLength: p.MaxLength}
+}
+
+// Render builds the final branch name from a model-generated
+// "<type>/<slug>" name, the issue ID and the policy pattern.
+func (p BranchPolicy) Render(generated, issueID string) string {
+	parts := strings.SplitN(generated, "/", 2)
+	branchType, slug := parts[0], parts[len(parts)-1]
+
+	issue := branchIssueID(issueID)
+	if strings.Contains(p.Pattern, "{issue}") && issue != "" {
+		// The pattern places the issue itself; drop it from the slug.
+		if len(slug) > len(issue) && strings.EqualFold(slug[len(slug)-len(issue):], issue) {
+			slug = strings.TrimRight(slug[:len(slug)-len(issue)], "-")
+		}
+	}
+
+	if p.Lowercase != LowercaseNone {
+		slug = strings.ToLower(slug)
+		// Tracker keys keep their case unless everything must be lowercase
+		if issue != "" && strings.HasSuffix(slug, strings.ToLower(issue)) {
+			slug = slug[:len(slug)-len(issue)] + issue
+		}
+	}
+
+	render := func(slug string) string {
+		name := placeholder.ReplaceAllStringFunc(p.Pattern, func(ph string) string {
+			switch ph {
+			case "{user}":
+				return p.User
+			case "{type}":
+				return branchType
+			case "{slug}":
+				return slug
+			case "{issue}":
+				return issue
+			}
+			return ph
+		})
+		// Tidy separators left by empty placeholders
+		for _, pair := range [][2]string{{"//", "/"}, {"--", "-"}, {"/-", "/"}, {"-/", "/"}} {
+			name = strings.ReplaceAll(name, pair[0], pair[1])
+		}
+		name = strings.Trim(name, "/-")
+		if p.Lowercase == LowercaseAll {
+			name = strings.ToLower(name)
+		}
+		return name
+	}
+
+	name := render(slug)
+
+	// Drop trailing slug words until the name fits
+	for p.MaxLength > 0 && len(name) > p.MaxLength {
+		idx := strings.LastIndex(slug, "-")
+		if idx <= 0 {
+			break
+		}
+		slug = slug[:idx]
+		name = render(slug)
+	}
+	return name
+}
+
+// patternRegexp turns the policy pattern into a regexp matching valid names.
+func (p BranchPolicy) patternRegexp() *regexp.Regexp {
+	types := make([]string, len(p.Types))
+	for i, t := range p.Types {
+		types[i] = regexp.QuoteMeta(t)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range placeholder.FindAllStringIndex(p.Pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(p.Pattern[last:loc[0]]))
+		switch p.Pattern[loc[0]:loc[1]] {
+		case "{user}":
+			b.WriteString(`[A-Za-z0-9._-]+`)
+		case "{type}":
+			b.WriteString("(?:" + strings.Join(types, "|") + ")")
+		case "{slug}":
+			b.WriteString(`[A-Za-z0-9._-]+`)
+		case "{issue}":
+			b.WriteString(`[A-Za-z0-9-]*`)
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(p.Pattern[last:]))
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Validate checks a branch name (generated or typed by hand) against the
+// policy and git's own ref rules.
+func (p BranchPolicy) Validate(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+	if _, err := shell.Run("git", "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("%q is not a valid git branch name", name)
+	}
+
+	if !p.patternRegexp().MatchString(name) {
+		return fmt.Errorf("%q does not match the branch pattern %q (allowed types: %s)", name, p.Pattern, strings.Join(p.Types, ", "))
+	}
+	if p.MaxLength > 0 && len(name) > p.MaxLength {
+		return fmt.Errorf("%q is %d characters; the limit is %d", name, len(name), p.MaxLength)
+	}
+
+	switch p.Lowercase {
+	case LowercaseAll:
+		if name != strings.ToLower(name) {
+			return fmt.Errorf("%q must be all lowercase", name)
+		}
+	case LowercaseSlug:
+		// Only tracker keys (ABC-123) may contain capitals
+		if strings.ToLower(trackerIssueRe.ReplaceAllString(name, "")) != trackerIssueRe.ReplaceAllString(name, "") {
+			return fmt.Errorf("%q must be lowercase (except issue IDs)", name)
+		}
+	}
+
+	if p.RequireIssue && DetectIssueID(name) == "" && !regexp.MustCompile(`(^|[/-])\d+($|[/-])`).MatchString(name) {
+		return fmt.Errorf("%q must include a ticket ID", name)
+	}
+	return nil
+}
+
+// CheckBranchName validates a branch name (the current branch when empty)
+// against the configured branch policy, e.g. from CI or a hook.
+func CheckBranchName(name string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	if strings.TrimSpace(name) == "" {
+		current, err := CurrentBranch()
+		if err != nil {
+			return fmt.Errorf("failed to get current branch: %w", err)
+		}
+		name = current
+	}
+
+	if err := LoadBranchPolicy().Validate(name); err != nil {
+		fmt.Println(ui.Red("❌ " + err.Error()))
+		return fmt.Errorf("branch name does not follow the policy")
+	}
+
+	fmt.Println(ui.Green("✔️ Branch name follows the policy:"), name)
+	return nil
+}
@@ -0,0 +1,154 @@
+package gitflow
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// How far back `git log` is searched for recent authors.
+const recentAuthorsSince = "90.days"
+
+// recentAuthors returns "Name <email>" for people who committed recently,
+// most active first, leaving out the current user.
+func recentAuthors(limit int) []string {
+	out, err := shell.Run("git", "log", "--since="+recentAuthorsSince, "--no-merges", "--format=%aN <%aE>")
+	if err != nil {
+		return nil
+	}
+
+	self := strings.ToLower(gitConfig("user.email"))
+
+	counts := map[string]int{}
+	var order []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || (self != "" && strings.HasSuffix(strings.ToLower(line), "<"+self+">")) {
+			continue
+		}
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+
+	slices.SortStableFunc(order, func(a, b string) int { return counts[b] - counts[a] })
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	return order
+}
+
+// githubCoAuthor turns a GitHub login into a trailer identity using the
+// user's no-reply address, which GitHub links to their account.
+func githubCoAuthor(login string) (string, error) {
+	cmd := exec.Command("gh", "api", "users/"+login, "--jq", `[.id, (.name // .login)] | @tsv`)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s on GitHub: %w", login, err)
+	}
+	id, name, ok := strings.Cut(strings.TrimSpace(string(out)), "\t")
+	if !ok || id == "" {
+		return "", fmt.Errorf("unexpected GitHub response for %s", login)
+	}
+	if name == "" {
+		name = login
+	}
+	return fmt.Sprintf("%s <%s+%s@users.noreply.github.com>", name, id, login), nil
+}
+
+// selectCoAuthors lets the user pick co-authors from recent committers and
+// the repo's GitHub collaborators. Returns "Name <email>" entries; an
+// empty result means no co-authors.
+func selectCoAuthors() ([]string, error) {
+	candidates := recentAuthors(15)
+
+	// Collaborators are optional; gh may be missing or not logged in
+	logins := map[string]string{}
+	if ghInstalled() {
+		if owner, repo, err := parseGitHubOwnerRepo(); err == nil {
+			if collaborators, err := getReviewers(owner, repo); err == nil {
+				for _, login := range collaborators {
+					item := "@" + login + " (GitHub)"
+					logins[item] = login
+					candidates = append(candidates, item)
+				}
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println(ui.Yellow("⚠️ No recent authors or collaborators found."))
+		name, err := ui.Input(ui.Cyan("Co-author as \"Name <email>\" (blank for none):"))
+		if err != nil || name == "" {
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+
+	fmt.Println()
+	fmt.Println("Possible co-authors:")
+	for i, c := range candidates {
+		fmt.Printf("  %2d) %s\n", i+1, c)
+	}
+	fmt.Println()
+
+	selected, err := ui.SelectMultiple(candidates, "Select co-authors by number (comma-separated, or blank for none):")
+	if err != nil {
+		return nil, err
+	}
+
+	var coAuthors []string
+	for _, s := range selected {
+		login, ok := logins[s]
+		if !ok {
+			coAuthors = append(coAuthors, s)
+			continue
+		}
+		who, err := githubCoAuthor(login)
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️"), err)
+			continue
+		}
+		coAuthors = append(coAuthors, who)
+	}
+	return coAuthors, nil
+}
+
+// withCoAuthors appends a Co-authored-by trailer per co-author, using git's
+// own trailer handling so existing trailers are kept in one block.
+func withCoAuthors(message string, coAuthors []string) string {
+	if len(coAuthors) == 0 || ai.IsWarning(message) {
+		return message
+	}
+
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent"}
+	for _, c := range coAuthors {
+		args = append(args, "--trailer", "Co-authored-by: "+c)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(message + "\n")
+	if out, err := cmd.Output(); err == nil {
+		return strings.TrimRight(string(out), "\n")
+	}
+
+	// Fall back to plain lines at the end
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(message, "\n"))
+	sb.WriteString("\n")
+	for _, c := range coAuthors {
+		sb.WriteString("\nCo-authored-by: " + c)
+	}
+	return sb.String()
+}
+
+// withTaskTrailers adds the task's issue reference and co-authors to a
+// generated commit message.
+func withTaskTrailers(message string, task *ActiveTask) string {
+	return withCoAuthors(withCommitIssueRef(message, task.IssueID), task.CoAuthors)
+}
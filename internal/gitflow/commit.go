@@ -0,0 +1,231 @@
+package gitflow
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// HookError reports a commit blocked by a git hook.
+type HookError struct {
+	Hook   string
+	Output string
+	// Modified lists staged files (repo-root relative) the hook changed in
+	// the working tree, typically by running a formatter.
+	Modified []string
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook failed", e.Hook)
+}
+
+// hookRunSupported reports whether this git has `git hook run` (2.36+),
+// which lets devgod run commit hooks itself and tell their failures apart.
+func hookRunSupported() bool {
+	_, err := shell.Run("git", "hook", "run", "--ignore-missing", "devgod-probe")
+	return err == nil
+}
+
+// fileHashes fingerprints the working tree copy of each path.
+func fileHashes(root string, paths []string) map[string][sha256.Size]byte {
+	hashes := make(map[string][sha256.Size]byte, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(root, p))
+		if err != nil {
+			continue
+		}
+		hashes[p] = sha256.Sum256(data)
+	}
+	return hashes
+}
+
+// changedFilesSince returns the paths whose working tree copy no longer
+// matches the fingerprint taken before.
+func changedFilesSince(root string, before map[string][sha256.Size]byte, paths []string) []string {
+	after := fileHashes(root, paths)
+	var changed []string
+	for _, p := range paths {
+		if after[p] != before[p] {
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
+
+// commit creates a commit with the given message. When git supports it,
+// the pre-commit and commit-msg hooks are run first by devgod so that a
+// failure comes back as a *HookError with the hook's output, instead of
+// a generic git error.
+func commit(message string, extra ...string) error {
+	return commitIn("", message, extra...)
+}
+
+// commitIn is commit for the index file at index ("" for the usual one).
+// The hooks see that index too.
+func commitIn(index, message string, extra ...string) error {
+	extra = append(signArgs(), extra...)
+	if planned("git", append([]string{"commit", "-m", message}, extra...)...) {
+		return nil
+	}
+	if !hookRunSupported() {
+		args := append([]string{"commit", "-m", message}, extra...)
+		out, err := gitIn(index, args...)
+		return signingError(out, err)
+	}
+
+	root, err := RepoRoot()
+	if err != nil {
+		return err
+	}
+	staged, _ := stagedPaths(index)
+	before := fileHashes(root, staged)
+
+	if out, err := gitIn(index, "-C", root, "hook", "run", "--ignore-missing", "pre-commit"); err != nil {
+		return &HookError{Hook: "pre-commit", Output: out, Modified: changedFilesSince(root, before, staged)}
+	}
+
+	// commit-msg may rewrite the message, so hand it a file
+	f, err := os.CreateTemp("", "devgod-commit-msg-*")
+	if err != nil {
+		return err
+	}
+	msgFile := f.Name()
+	defer os.Remove(msgFile)
+	if _, err := f.WriteString(message + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+
+	if out, err := gitIn(index, "-C", root, "hook", "run", "--ignore-missing", "commit-msg", "--", msgFile); err != nil {
+		return &HookError{Hook: "commit-msg", Output: out}
+	}
+
+	// The hooks already ran; don't run them twice
+	args := append([]string{"-C", root, "commit", "--no-verify", "-F", msgFile}, extra...)
+	out, err := gitIn(index, args...)
+	return signingError(out, err)
+}
+
+// commitNoVerify commits the index file at index ("" for the usual one)
+// while skipping the pre-commit and commit-msg hooks.
+func commitNoVerify(index, message string, extra ...string) error {
+	args := append([]string{"commit", "--no-verify", "-m", message}, signArgs()...)
+	args = append(args, extra...)
+	if planned("git", args...) {
+		return nil
+	}
+	out, err := gitIn(index, args...)
+	return signingError(out, err)
+}
+
+// printHookFailure shows a failed hook's output in a panel.
+func printHookFailure(hookErr *HookError) {
+	fmt.Println()
+	fmt.Println("🪝 " + ui.Red(fmt.Sprintf("The %s hook blocked the commit:", hookErr.Hook)))
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+	out := strings.TrimRight(hookErr.Output, "\n")
+	if strings.TrimSpace(out) == "" {
+		out = "(the hook printed nothing)"
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fmt.Println("  " + line)
+	}
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+}
+
+const (
+	hookRetry  = "Retry the commit (I fixed it)"
+	hookSkip   = "Commit anyway, skipping hooks (--no-verify)"
+	hookCancel = "Cancel"
+)
+
+// commitWithRetry commits and, when a hook blocks the commit, shows why and
+// lets the user fix things and retry with the same message. Files a hook
+// reformatted can be re-staged first. A commit git could not sign can be
+// retried once the key is sorted out. Without a terminal the hook or signing
+// error is returned as is. It reports whether a commit was made, which is
+// never the case in a dry run.
+func commitWithRetry(message string, extra ...string) (bool, error) {
+	return commitWithRetryIn("", message, extra...)
+}
+
+// commitWithRetryIn is commitWithRetry for the index file at index ("" for
+// the usual one). Re-staged files go to that index.
+func commitWithRetryIn(index, message string, extra ...string) (bool, error) {
+	if dryRun {
+		return false, commitIn(index, message, extra...)
+	}
+	for {
+		err := commitIn(index, message, extra...)
+		if err == nil {
+			return true, nil
+		}
+
+		var signErr *SigningError
+		if errors.As(err, &signErr) {
+			printSigningFailure(signErr)
+			if ui.Offer("Retry the commit (I fixed the signing setup)?") {
+				continue
+			}
+			if !ui.Interactive() || ui.AssumeYes() {
+				return false, err
+			}
+			fmt.Println("❌ Commit cancelled. Your staged changes are untouched; the message was:")
+			fmt.Println(message)
+			return false, nil
+		}
+
+		var hookErr *HookError
+		if !errors.As(err, &hookErr) {
+			return false, err
+		}
+		printHookFailure(hookErr)
+
+		// Nobody is there to fix things and retry
+		if !ui.Interactive() {
+			return false, err
+		}
+
+		if len(hookErr.Modified) > 0 {
+			fmt.Println(ui.Yellow("The hook changed these staged files:"))
+			for _, f := range hookErr.Modified {
+				fmt.Println("   " + f)
+			}
+			if ui.Confirm("Re-stage them with the hook's changes?") {
+				args := append([]string{"add", "-A", "--"}, rootPaths(hookErr.Modified)...)
+				if _, err := gitIn(index, args...); err != nil {
+					return false, err
+				}
+				fmt.Println(ui.Green("✔️ Re-staged."))
+			}
+		}
+
+		actions := []string{hookRetry, hookSkip, hookCancel}
+		for i, a := range actions {
+			fmt.Printf("  %2d) %s\n", i+1, a)
+		}
+		action, err := ui.SelectOne(actions, ui.Cyan("What next?"))
+		if err != nil {
+			return false, err
+		}
+
+		switch action {
+		case hookSkip:
+			if err := commitNoVerify(index, message, extra...); err != nil {
+				return false, err
+			}
+			return true, nil
+		case hookCancel:
+			fmt.Println("❌ Commit cancelled. Your staged changes are untouched; the message was:")
+			fmt.Println(message)
+			return false, nil
+		}
+	}
+}
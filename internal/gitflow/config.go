@@ -0,0 +1,35 @@
+package gitflow
+
+import (
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+)
+
+// configValue reads a devgod setting from git config (e.g. key "base" reads
+// `devgod.base`), so settings can live per repo or globally. Returns "" when
+// unset.
+func configValue(key string) string {
+	return gitConfig("devgod." + key)
+}
+
+// gitConfig reads any git config value, returning "" when unset.
+func gitConfig(key string) string {
+	out, err := shell.Run("git", "config", "--get", key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// splitList parses a comma-separated setting such as "feat, fix, chore",
+// dropping empty entries.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,404 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Lines of surrounding code sent to the model with each hunk.
+const conflictContextLines = 10
+
+// conflictHunk is one <<<<<<< ... >>>>>>> block. Ours and Theirs follow
+// git's naming; which one is the task branch depends on rebase vs merge.
+type conflictHunk struct {
+	Raw     string // the whole block including markers
+	Ours    string
+	Base    string
+	HasBase bool
+	Theirs  string
+
+	Resolution string
+	Resolved   bool
+}
+
+// conflictPart is either plain text or a conflict hunk.
+type conflictPart struct {
+	Text string
+	Hunk *conflictHunk
+}
+
+func isMarker(line, marker string) bool {
+	line = strings.TrimRight(line, "\r\n")
+	return line == marker || strings.HasPrefix(line, marker+" ")
+}
+
+// parseConflicts splits file content into text and conflict hunks. Both
+// merge and diff3 marker styles are understood.
+func parseConflicts(content string) []conflictPart {
+	var parts []conflictPart
+	var text strings.Builder
+	var hunk *conflictHunk
+	var raw, section *strings.Builder
+	var ours, base, theirs strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			parts = append(parts, conflictPart{Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		switch {
+		case hunk == nil && isMarker(line, "<<<<<<<"):
+			flushText()
+			hunk = &conflictHunk{}
+			raw = &strings.Builder{}
+			ours.Reset()
+			base.Reset()
+			theirs.Reset()
+			section = &ours
+			raw.WriteString(line)
+		case hunk != nil && section == &ours && isMarker(line, "|||||||"):
+			hunk.HasBase = true
+			section = &base
+			raw.WriteString(line)
+		case hunk != nil && section != &theirs && isMarker(line, "======="):
+			section = &theirs
+			raw.WriteString(line)
+		case hunk != nil && section == &theirs && isMarker(line, ">>>>>>>"):
+			raw.WriteString(line)
+			hunk.Raw = raw.String()
+			hunk.Ours = ours.String()
+			hunk.Base = base.String()
+			hunk.Theirs = theirs.String()
+			parts = append(parts, conflictPart{Hunk: hunk})
+			hunk = nil
+		case hunk != nil:
+			section.WriteString(line)
+			raw.WriteString(line)
+		default:
+			text.WriteString(line)
+		}
+	}
+
+	// An unterminated block is left as plain text
+	if hunk != nil {
+		text.WriteString(raw.String())
+	}
+	flushText()
+	return parts
+}
+
+// renderConflicts rebuilds the file, using resolutions where accepted and
+// the original markers elsewhere.
+func renderConflicts(parts []conflictPart) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		switch {
+		case p.Hunk == nil:
+			sb.WriteString(p.Text)
+		case p.Hunk.Resolved:
+			sb.WriteString(p.Hunk.Resolution)
+		default:
+			sb.WriteString(p.Hunk.Raw)
+		}
+	}
+	return sb.String()
+}
+
+// hunksOf returns the conflict hunks in order.
+func hunksOf(parts []conflictPart) []*conflictHunk {
+	var hunks []*conflictHunk
+	for _, p := range parts {
+		if p.Hunk != nil {
+			hunks = append(hunks, p.Hunk)
+		}
+	}
+	return hunks
+}
+
+// fillConflictBases adds the common ancestor to hunks that lack it, by
+// re-merging the index stages with diff3 markers in memory. Hunks are
+// matched by content, so blocks the user already edited are left alone.
+func fillConflictBases(root, file string, hunks []*conflictHunk) {
+	for _, h := range hunks {
+		if h.HasBase {
+			return
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "devgod-conflict-*")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// Stages 1, 2 and 3 are base, ours and theirs
+	var paths [3]string
+	for i, stage := range []string{"1", "2", "3"} {
+		blob, err := shell.Run("git", "-C", root, "show", ":"+stage+":"+file)
+		if err != nil {
+			return
+		}
+		paths[i] = filepath.Join(dir, stage)
+		if err := os.WriteFile(paths[i], []byte(blob), 0o600); err != nil {
+			return
+		}
+	}
+
+	// merge-file exits with the number of conflicts, so ignore its error
+	out, _ := shell.Run("git", "merge-file", "-p", "--diff3", paths[1], paths[0], paths[2])
+	merged := hunksOf(parseConflicts(out))
+	for _, h := range hunks {
+		for _, m := range merged {
+			if m.HasBase && m.Ours == h.Ours && m.Theirs == h.Theirs {
+				h.Base = m.Base
+				h.HasBase = true
+				break
+			}
+		}
+	}
+}
+
+// surroundingText returns up to n lines of plain text before and after the
+// hunk at index i.
+func surroundingText(parts []conflictPart, i, n int) (before, after string) {
+	if i > 0 && parts[i-1].Hunk == nil {
+		lines := strings.SplitAfter(parts[i-1].Text, "\n")
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > n {
+			lines = lines[len(lines)-n:]
+		}
+		before = strings.Join(lines, "")
+	}
+	if i+1 < len(parts) && parts[i+1].Hunk == nil {
+		lines := strings.SplitAfter(parts[i+1].Text, "\n")
+		if len(lines) > n {
+			lines = lines[:n]
+		}
+		after = strings.Join(lines, "")
+	}
+	return before, after
+}
+
+// lineDiff returns a minimal line diff from a to b, each line prefixed with
+// ' ', '-' or '+'.
+func lineDiff(a, b []string) []string {
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+"+b[j])
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// printSuggestion shows the proposed resolution as a diff against each side.
+func printSuggestion(mine, upstream, suggestion string) {
+	for _, side := range []struct{ label, text string }{
+		{"Changes to your version:", mine},
+		{"Changes to the base branch's version:", upstream},
+	} {
+		fmt.Println("   " + ui.Dim(side.label))
+		for _, line := range lineDiff(splitLines(side.text), splitLines(suggestion)) {
+			switch line[0] {
+			case '+':
+				fmt.Println("   " + ui.Green(line))
+			case '-':
+				fmt.Println("   " + ui.Red(line))
+			default:
+				fmt.Println("   " + line)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// editText opens text in the editor and returns the saved result.
+func editText(text, ext string) (string, error) {
+	f, err := os.CreateTemp("", "devgod-resolution-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	name := f.Name()
+	defer os.Remove(name)
+
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	if err := openInEditor(name); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+const (
+	hunkAccept = "Accept"
+	hunkEdit   = "Edit it first"
+	hunkSkip   = "Skip this hunk"
+)
+
+// resolveWithAI walks the conflict hunks of one file, asking the model for
+// a resolution of each and letting the user accept, edit or skip it. The
+// file is written back, and staged when no conflicts remain. It reports
+// whether the file is fully resolved.
+func resolveWithAI(root, file, op string) bool {
+	path := filepath.Join(root, file)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not read the file:"), err)
+		return false
+	}
+
+	parts := parseConflicts(string(data))
+	hunks := hunksOf(parts)
+	if len(hunks) == 0 {
+		fmt.Println(ui.Yellow("⚠️ No conflict markers found; use another option for this file."))
+		return false
+	}
+	fillConflictBases(root, file, hunks)
+
+	n := 0
+	for i, p := range parts {
+		h := p.Hunk
+		if h == nil {
+			continue
+		}
+		n++
+
+		// During a rebase "ours" is the base branch being replayed onto
+		mine, upstream := h.Ours, h.Theirs
+		if op == syncRebase {
+			mine, upstream = h.Theirs, h.Ours
+		}
+		before, after := surroundingText(parts, i, conflictContextLines)
+
+		stop := ui.StartSpinner(fmt.Sprintf("Asking AI about hunk %d of %d...", n, len(hunks)))
+		suggestion, err := ai.SuggestConflictResolution(ai.ConflictHunk{
+			Path:     file,
+			Mine:     mine,
+			Upstream: upstream,
+			Base:     h.Base,
+			Before:   before,
+			After:    after,
+		})
+		stop()
+
+		fmt.Println()
+		fmt.Println("🤖 " + ui.SectionTitleStyle.Render(fmt.Sprintf("%s — hunk %d of %d:", file, n, len(hunks))))
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️ No suggestion:"), err)
+			continue
+		}
+
+		// Match the hunk's trailing newline so surrounding lines stay intact
+		if strings.HasSuffix(h.Ours, "\n") && suggestion != "" && !strings.HasSuffix(suggestion, "\n") {
+			suggestion += "\n"
+		}
+		printSuggestion(mine, upstream, suggestion)
+
+		actions := []string{hunkAccept, hunkEdit, hunkSkip}
+		for j, a := range actions {
+			fmt.Printf("  %2d) %s\n", j+1, a)
+		}
+		action, err := ui.SelectOne(actions, ui.Cyan("Use this resolution?"))
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️"), err)
+			break
+		}
+
+		switch action {
+		case hunkAccept:
+			h.Resolution, h.Resolved = suggestion, true
+		case hunkEdit:
+			edited, err := editText(suggestion, filepath.Ext(file))
+			if err != nil {
+				fmt.Println(ui.Yellow("⚠️ Could not open the editor:"), err)
+				continue
+			}
+			h.Resolution, h.Resolved = edited, true
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if plannedAction("write the accepted resolutions to " + file) {
+		return false
+	}
+	if err := os.WriteFile(path, []byte(renderConflicts(parts)), info.Mode()); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not write the file:"), err)
+		return false
+	}
+
+	left := 0
+	for _, h := range hunks {
+		if !h.Resolved {
+			left++
+		}
+	}
+	if left > 0 {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ %d hunk(s) in %s still need resolving.", left, file)))
+		return false
+	}
+	return markResolved(root, file)
+}
@@ -0,0 +1,125 @@
+package gitflow
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []conflictPart
+	}{
+		{
+			name:    "no conflicts",
+			content: "a\nb\n",
+			want:    []conflictPart{{Text: "a\nb\n"}},
+		},
+		{
+			name:    "merge style",
+			content: "top\n<<<<<<< HEAD\nmine\n=======\ntheirs\n>>>>>>> main\nbottom\n",
+			want: []conflictPart{
+				{Text: "top\n"},
+				{Hunk: &conflictHunk{
+					Raw:    "<<<<<<< HEAD\nmine\n=======\ntheirs\n>>>>>>> main\n",
+					Ours:   "mine\n",
+					Theirs: "theirs\n",
+				}},
+				{Text: "bottom\n"},
+			},
+		},
+		{
+			name:    "diff3 style",
+			content: "<<<<<<< ours\nmine\n||||||| base\norig\n=======\ntheirs\n>>>>>>> theirs\n",
+			want: []conflictPart{
+				{Hunk: &conflictHunk{
+					Raw:     "<<<<<<< ours\nmine\n||||||| base\norig\n=======\ntheirs\n>>>>>>> theirs\n",
+					Ours:    "mine\n",
+					Base:    "orig\n",
+					HasBase: true,
+					Theirs:  "theirs\n",
+				}},
+			},
+		},
+		{
+			name:    "two blocks",
+			content: "<<<<<<< a\n1\n=======\n2\n>>>>>>> b\nmid\n<<<<<<< a\n3\n=======\n>>>>>>> b\n",
+			want: []conflictPart{
+				{Hunk: &conflictHunk{Raw: "<<<<<<< a\n1\n=======\n2\n>>>>>>> b\n", Ours: "1\n", Theirs: "2\n"}},
+				{Text: "mid\n"},
+				{Hunk: &conflictHunk{Raw: "<<<<<<< a\n3\n=======\n>>>>>>> b\n", Ours: "3\n"}},
+			},
+		},
+		{
+			name:    "marker-like text is not a marker",
+			content: "<<<<<<<<< not a marker\n=======x\n",
+			want:    []conflictPart{{Text: "<<<<<<<<< not a marker\n=======x\n"}},
+		},
+		{
+			name:    "unterminated block stays text",
+			content: "a\n<<<<<<< HEAD\nmine\n=======\n",
+			want:    []conflictPart{{Text: "a\n"}, {Text: "<<<<<<< HEAD\nmine\n=======\n"}},
+		},
+		{
+			name:    "CRLF line endings",
+			content: "<<<<<<< HEAD\r\nmine\r\n=======\r\ntheirs\r\n>>>>>>> main\r\n",
+			want: []conflictPart{
+				{Hunk: &conflictHunk{
+					Raw:    "<<<<<<< HEAD\r\nmine\r\n=======\r\ntheirs\r\n>>>>>>> main\r\n",
+					Ours:   "mine\r\n",
+					Theirs: "theirs\r\n",
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseConflicts(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConflicts = %s, want %s", describeParts(got), describeParts(tt.want))
+			}
+			if out := renderConflicts(got); out != tt.content {
+				t.Errorf("renderConflicts = %q, want the original %q", out, tt.content)
+			}
+		})
+	}
+}
+
+// describeParts prints parts with their hunks expanded.
+func describeParts(parts []conflictPart) string {
+	var out []string
+	for _, p := range parts {
+		if p.Hunk != nil {
+			out = append(out, fmt.Sprintf("hunk%+v", *p.Hunk))
+		} else {
+			out = append(out, fmt.Sprintf("text(%q)", p.Text))
+		}
+	}
+	return "[" + strings.Join(out, " ") + "]"
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{name: "equal", a: []string{"x", "y"}, b: []string{"x", "y"}, want: []string{" x", " y"}},
+		{name: "both empty", want: nil},
+		{name: "all added", b: []string{"x"}, want: []string{"+x"}},
+		{name: "all removed", a: []string{"x"}, want: []string{"-x"}},
+		{name: "changed line", a: []string{"a", "b", "c"}, b: []string{"a", "B", "c"}, want: []string{" a", "-b", "+B", " c"}},
+		{name: "insert in middle", a: []string{"a", "c"}, b: []string{"a", "b", "c"}, want: []string{" a", "+b", " c"}},
+		{name: "trailing removal", a: []string{"a", "b", "c"}, b: []string{"a"}, want: []string{" a", "-b", "-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiff(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lineDiff = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package gitflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// What to do with uncommitted work when starting a new task.
+const (
+	dirtyCarry   = "Carry them to the new branch"
+	dirtyMove    = "Stash them and restore them on the new branch"
+	dirtyStash   = "Stash them and leave them stashed"
+	dirtyWIP     = "Commit them as WIP on the current branch"
+	dirtyCancel  = "Cancel"
+	stashMessage = "devgod: uncommitted work before starting %s"
+)
+
+// StashPush stashes tracked and untracked changes with a message.
+func StashPush(message string) error {
+	_, err := mutate("git", "stash", "push", "--include-untracked", "-m", message)
+	return err
+}
+
+// StashPop re-applies and drops the most recent stash.
+func StashPop() error {
+	_, err := mutate("git", "stash", "pop")
+	return err
+}
+
+// dirtyPlan is the outcome of applyDirtyChoice.
+type dirtyPlan struct {
+	// restoreStash is true when the stash must be popped on the new branch.
+	restoreStash bool
+}
+
+// askDirtyTree asks what should happen to uncommitted changes when a task
+// starts. It runs before the branch is named or the base fetched, so
+// cancelling wastes neither. It returns "" for a clean tree.
+func askDirtyTree(currentBranch string) (string, error) {
+	summary, _ := WorkingTreeStatus()
+	if strings.TrimSpace(summary) == "" {
+		return "", nil
+	}
+	if currentBranch == "" {
+		currentBranch = "HEAD"
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Yellow("⚠️ You have uncommitted changes on " + currentBranch + ":"))
+	for _, line := range strings.Split(summary, "\n") {
+		if strings.TrimSpace(line) != "" {
+			fmt.Println("   " + ui.ColorizeStatusLine(line))
+		}
+	}
+	fmt.Println()
+
+	// Unattended runs keep git's default of carrying the changes over
+	if ui.AssumeYes() {
+		fmt.Println(ui.Dim("Bringing them to the new branch (--yes)."))
+		return dirtyCarry, nil
+	}
+
+	options := []string{dirtyCarry, dirtyMove, dirtyStash, dirtyWIP, dirtyCancel}
+	for i, o := range options {
+		fmt.Printf("  %2d) %s\n", i+1, o)
+	}
+	fmt.Println()
+
+	return ui.SelectOne(options, ui.Cyan("What should happen to these changes?"))
+}
+
+// dirtyPaths lists every path with staged, unstaged or untracked changes,
+// relative to the repo root.
+func dirtyPaths() []string {
+	out, err := shell.Run("git", "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		if len(e) < 4 {
+			continue
+		}
+		paths = append(paths, e[3:])
+		// Renames/copies are followed by the original path
+		if e[0] == 'R' || e[0] == 'C' {
+			i++
+			if i < len(entries) && entries[i] != "" {
+				paths = append(paths, entries[i])
+			}
+		}
+	}
+	return paths
+}
+
+// carryConflicts lists changed paths that also differ between HEAD and
+// target. git checkout refuses to carry those over.
+func carryConflicts(target string) []string {
+	out, err := shell.Run("git", "diff", "--name-only", "-z", "HEAD", target)
+	if err != nil {
+		return nil
+	}
+	differs := map[string]bool{}
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			differs[p] = true
+		}
+	}
+
+	var conflicts []string
+	for _, p := range dirtyPaths() {
+		if differs[p] {
+			conflicts = append(conflicts, p)
+		}
+	}
+	return conflicts
+}
+
+// applyDirtyChoice carries out the answer from askDirtyTree once the new
+// branch and the commit it starts at (target) are known. Carrying changes
+// that checkout would refuse falls back to moving them through the stash.
+func applyDirtyChoice(choice, currentBranch, newBranch, target string) (dirtyPlan, error) {
+	if currentBranch == "" {
+		currentBranch = "HEAD"
+	}
+
+	if choice == dirtyCarry {
+		conflicts := carryConflicts(target)
+		if len(conflicts) == 0 {
+			return dirtyPlan{}, nil
+		}
+		fmt.Println(ui.Yellow("⚠️ These changes clash with " + newBranch + ", so git cannot carry them over:"))
+		for _, p := range conflicts {
+			fmt.Println("   " + p)
+		}
+		fmt.Println("Moving them through the stash instead.")
+		choice = dirtyMove
+	}
+
+	switch choice {
+	case dirtyMove, dirtyStash:
+		if err := StashPush(fmt.Sprintf(stashMessage, newBranch)); err != nil {
+			return dirtyPlan{}, fmt.Errorf("failed to stash changes: %w", err)
+		}
+		fmt.Println(ui.Green("✔️ Changes stashed."))
+		return dirtyPlan{restoreStash: choice == dirtyMove}, nil
+
+	case dirtyWIP:
+		if err := StageAll(); err != nil {
+			return dirtyPlan{}, err
+		}
+		if err := Commit(fmt.Sprintf("wip: save work before starting %s", newBranch)); err != nil {
+			return dirtyPlan{}, fmt.Errorf("failed to create WIP commit: %w", err)
+		}
+		fmt.Println(ui.Green("✔️ WIP commit created on"), currentBranch)
+	}
+	return dirtyPlan{}, nil
+}
+
+// restoreStashedWork pops the stash created by applyDirtyChoice. A conflict
+// leaves the stash in place so nothing is lost.
+func restoreStashedWork() {
+	if err := StashPop(); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not restore your stashed changes cleanly."))
+		fmt.Println("They are still in the stash; resolve any conflicts and run:")
+		fmt.Println("  git stash pop")
+		return
+	}
+	fmt.Println(ui.Green("✔️ Restored your changes on the new branch."))
+}
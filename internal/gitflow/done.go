@@ -0,0 +1,219 @@
+package gitflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// mergedPR is the subset of `gh pr view --json` used to decide whether a
+// task is done.
+type mergedPR struct {
+	Number      int    `json:"number"`
+	State       string `json:"state"`
+	BaseRefName string `json:"baseRefName"`
+	HeadRefOid  string `json:"headRefOid"`
+}
+
+func fetchMergedPR(branch string) (*mergedPR, error) {
+	cmd := exec.Command("gh", "pr", "view", branch, "--json", "number,state,baseRefName,headRefOid")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &mergedPR{}
+	if err := json.Unmarshal(out, pr); err != nil {
+		return nil, fmt.Errorf("failed to parse gh pr view output: %w", err)
+	}
+	return pr, nil
+}
+
+// checkTaskMerged verifies the task branch has no work that is missing from
+// its base. With a merged PR the branch may only contain what the PR
+// contained (squash/rebase merges rewrite commits); without one, every
+// commit must already be in origin/<base>.
+func checkTaskMerged(branch, base string, pr *mergedPR) error {
+	if pr != nil && pr.State == "MERGED" {
+		if pr.HeadRefOid == "" || !RefExists(pr.HeadRefOid) {
+			return nil
+		}
+		n, err := CountCommits(pr.HeadRefOid, branch)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("branch %s has %d commit(s) that were not part of merged PR #%d", branch, n, pr.Number)
+		}
+		return nil
+	}
+
+	if pr != nil {
+		return fmt.Errorf("PR #%d for %s is %s, not merged", pr.Number, branch, strings.ToLower(pr.State))
+	}
+
+	n, err := CountCommits("origin/"+base, branch)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("branch %s has %d commit(s) not merged into origin/%s", branch, n, base)
+	}
+	return nil
+}
+
+// Done cleans up after a task's PR is merged: it switches to the base
+// branch, fast-forwards it, deletes the task branch (and optionally its
+// remote) and archives the task.
+func Done(branch string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	current, err := CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if strings.TrimSpace(branch) == "" {
+		branch = current
+	}
+
+	task := state.TaskForBranch(branch)
+	if task == nil {
+		return fmt.Errorf("no task found for branch %s", branch)
+	}
+
+	// The worktree is removed before the branch can go, and nothing can run
+	// in a directory that no longer exists
+	if task.Worktree != "" && insideDir(task.Worktree) {
+		main := "the main checkout"
+		if common, err := GitCommonDir(); err == nil {
+			main = filepath.Dir(common)
+		}
+		fmt.Println(ui.Yellow("⚠️ You are inside this task's worktree, which `devgod done` removes."))
+		fmt.Println("Run it from the main checkout instead:")
+		fmt.Printf("  cd %s && devgod done %s\n", main, branch)
+		return fmt.Errorf("cannot finish %s from inside its own worktree", branch)
+	}
+
+	stop := ui.StartSpinner("Fetching latest from origin...")
+	err = FetchOrigin("--prune")
+	stop()
+	if err != nil {
+		return fmt.Errorf("failed to fetch origin: %w", err)
+	}
+
+	// Look up the PR; gh is optional here
+	var pr *mergedPR
+	if ghInstalled() {
+		if p, err := fetchMergedPR(branch); err == nil {
+			pr = p
+		}
+	}
+
+	base := task.BaseBranch
+	if pr != nil && pr.BaseRefName != "" {
+		base = pr.BaseRefName
+	}
+	if base == "" {
+		base = DefaultRemoteBranch()
+	}
+	if base == "" {
+		return fmt.Errorf("could not determine the base branch for %s", branch)
+	}
+
+	if err := checkTaskMerged(branch, base, pr); err != nil {
+		fmt.Println(ui.Red("❌ This task does not look finished:"), err)
+		fmt.Println("Merge the PR (or push the missing commits) and try again.")
+		return fmt.Errorf("task %s has unmerged work", branch)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("🧹 DEVGOD DONE"))
+	fmt.Println("🌿 " + ui.BranchLabelStyle.Render("Task branch:"))
+	fmt.Println("   " + ui.ValueStyle.Render(branch))
+	if pr != nil {
+		fmt.Printf("   %s\n", ui.Green(fmt.Sprintf("PR #%d merged", pr.Number)))
+	}
+	fmt.Println("🧱 " + ui.SectionTitleStyle.Render("Base branch:"))
+	fmt.Println("   " + ui.ValueStyle.Render(base))
+	fmt.Println()
+
+	if !ui.Confirm(fmt.Sprintf("Switch to %s, update it and delete %s?", base, branch)) {
+		fmt.Println("❌ Cleanup cancelled.")
+		return nil
+	}
+
+	// A worktree task must lose its worktree before the branch can go
+	if task.Worktree != "" {
+		if worktreeHasChanges(task.Worktree) {
+			return fmt.Errorf("worktree %s has uncommitted changes", task.Worktree)
+		}
+		if err := RemoveWorktree(task.Worktree); err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+		if !dryRun {
+			fmt.Println(ui.Green("✔️ Worktree removed:"), task.Worktree)
+		}
+	}
+
+	// Move off the task branch and bring the base up to date
+	if current == branch {
+		if err := CheckoutBranch(base); err != nil {
+			return fmt.Errorf("failed to switch to %s: %w", base, err)
+		}
+		current = base
+	}
+	if current == base {
+		if err := FastForward("origin/" + base); err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not fast-forward "+base+":"), err)
+		} else if !dryRun {
+			fmt.Println(ui.Green("✔️ Updated"), base)
+		}
+	} else if err := FetchOrigin(base + ":" + base); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not fast-forward " + base + " (you are on " + current + ")."))
+	}
+
+	if err := DeleteLocalBranch(branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	if !dryRun {
+		fmt.Println(ui.Green("✔️ Deleted local branch"), branch)
+	}
+
+	if IsBranchPushed(branch) && ui.Offer(fmt.Sprintf("Also delete origin/%s?", branch)) {
+		if err := DeleteRemoteBranch(branch); err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not delete the remote branch:"), err)
+		} else if !dryRun {
+			fmt.Println(ui.Green("✔️ Deleted remote branch"), "origin/"+branch)
+		}
+	}
+
+	err = UpdateState(func(s *RepoState) error {
+		if t := s.TaskForBranch(branch); t != nil {
+			t.Worktree = ""
+			if pr != nil {
+				t.PRState = pr.State
+			}
+		}
+		s.ArchiveTask(branch)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		fmt.Println(ui.Green("✅ Task archived. Nice work!"))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// dryRun is set by --dry-run: commands that change the repo, GitHub or a
+// ticket tracker are printed instead of run.
+var dryRun bool
+
+// DryRun reports whether --dry-run is on.
+func DryRun() bool {
+	return dryRun
+}
+
+// EnableDryRun turns on dry-run mode. Staging still happens, but into a
+// scratch copy of the index, so later steps see exactly what would be
+// committed while the real index is left alone. The returned function
+// removes the scratch index.
+func EnableDryRun() (func(), error) {
+	dryRun = true
+	fmt.Println(ui.Yellow("🧪 Dry run: nothing will be changed; commands are printed instead."))
+
+	if !IsGitRepo() {
+		return func() {}, nil
+	}
+	index, err := GitPath("index")
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp("", "devgod-dry-run-index-*")
+	if err != nil {
+		return nil, err
+	}
+	scratch := f.Name()
+	f.Close()
+	cleanup := func() { os.Remove(scratch) }
+
+	// A missing index (fresh repo) is an empty one
+	if data, err := os.ReadFile(index); err == nil {
+		if err := os.WriteFile(scratch, data, 0o600); err != nil {
+			cleanup()
+			return nil, err
+		}
+	} else {
+		os.Remove(scratch)
+	}
+
+	// Every git command from here on, including ours, uses the scratch index
+	if err := os.Setenv("GIT_INDEX_FILE", scratch); err != nil {
+		cleanup()
+		return nil, err
+	}
+	return cleanup, nil
+}
+
+var plainArg = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,^~-]+$`)
+
+// shellQuote renders a command line that can be pasted into a shell.
+func shellQuote(name string, args ...string) string {
+	parts := []string{name}
+	for _, a := range args {
+		parts = append(parts, quoteArg(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteArg quotes one word for sh, leaving plain words readable. Inside
+// single quotes nothing is special, so only ' itself needs escaping.
+func quoteArg(a string) string {
+	if plainArg.MatchString(a) {
+		return a
+	}
+	return "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+}
+
+// announce prints a command as part of the dry-run plan.
+func announce(name string, args ...string) {
+	if dryRun {
+		fmt.Println(ui.Yellow("[dry-run]"), shellQuote(name, args...))
+	}
+}
+
+// planned prints a command that changes something and reports true in a
+// dry run, in which case the caller must not run it.
+func planned(name string, args ...string) bool {
+	announce(name, args...)
+	return dryRun
+}
+
+// plannedAction is planned for changes that are not a single command, such
+// as moving a ticket.
+func plannedAction(description string) bool {
+	if dryRun {
+		fmt.Println(ui.Yellow("[dry-run]"), description)
+	}
+	return dryRun
+}
+
+// mutate runs a command that changes the repo, or only prints it in a dry
+// run.
+func mutate(name string, args ...string) (string, error) {
+	if planned(name, args...) {
+		return "", nil
+	}
+	return shell.Run(name, args...)
+}
+
+// printStatePlan shows how the state file would change, as the changed
+// lines of its JSON.
+func printStatePlan(path string, data []byte) {
+	old, _ := os.ReadFile(path)
+	if string(old) == string(data) {
+		return
+	}
+	fmt.Println(ui.Yellow("[dry-run]"), "update", path+":")
+	for _, line := range lineDiff(splitLines(string(old)), splitLines(string(data))) {
+		switch line[0] {
+		case '+':
+			fmt.Println("   " + ui.Green(line))
+		case '-':
+			fmt.Println("   " + ui.Red(line))
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Git's well-known empty tree, used as the parent of a root commit.
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// branchCommit is a commit on the task branch.
+type branchCommit struct {
+	Hash    string
+	Subject string
+}
+
+// taskBranchCommits lists the commits on the task branch that are not on its
+// base, newest first. Without a known base only commits devgod recorded for
+// the task count, from HEAD back to the first one it did not make, so
+// nothing that is already on the base can be rewritten.
+func taskBranchCommits(task *ActiveTask) ([]branchCommit, error) {
+	args := []string{"log", "--format=%H%x00%s"}
+	base := taskBaseRef(task.BaseBranch)
+	if base != "" {
+		args = append(args, base+"..HEAD")
+	} else {
+		args = append(args, "-n", strconv.Itoa(len(task.Commits)), "HEAD")
+	}
+	if base == "" && len(task.Commits) == 0 {
+		return nil, nil
+	}
+
+	out, err := shell.Run("git", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := map[string]bool{}
+	for _, c := range task.Commits {
+		recorded[c.Hash] = true
+	}
+
+	var commits []branchCommit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		hash, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		if base == "" && !recorded[hash] {
+			break
+		}
+		commits = append(commits, branchCommit{Hash: hash, Subject: subject})
+	}
+	return commits, nil
+}
+
+// isPushed reports whether commit is already part of origin/<branch>.
+func isPushed(commit, branch string) bool {
+	if !RefExists("refs/remotes/origin/" + branch) {
+		return false
+	}
+	_, err := shell.Run("git", "merge-base", "--is-ancestor", commit, "origin/"+branch)
+	return err == nil
+}
+
+// AmendCommit amends the last commit with the staged changes.
+func AmendCommit(message string) error {
+	return commit(message, "--amend")
+}
+
+// amendTask folds any staged changes into the last commit on the task
+// branch and regenerates its message from the combined diff.
+func amendTask(task *ActiveTask) error {
+	commits, err := taskBranchCommits(task)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Println(ui.Yellow("⚠️ There is no commit on this task branch to amend."))
+		if taskBaseRef(task.BaseBranch) == "" {
+			fmt.Println(ui.Dim("The task's base branch is unknown, so only commits devgod made for it can be changed."))
+		}
+		return nil
+	}
+	head := commits[0]
+
+	if err := prepareIndex(); err != nil {
+		return err
+	}
+
+	// A fixup commit must keep its subject so autosquash can find the target
+	if isAutosquashSubject(head.Subject) {
+		if !HasStagedChanges() {
+			fmt.Println("No staged changes to fold into the last commit.")
+			return nil
+		}
+		if !ui.Confirm(fmt.Sprintf("Fold the staged changes into %q?", head.Subject)) {
+			fmt.Println("❌ Amend cancelled.")
+			return nil
+		}
+		// Same message as before, but through the usual hook handling
+		message, err := shell.Run("git", "log", "-1", "--format=%B", "HEAD")
+		if err != nil {
+			return err
+		}
+		amended, err := commitWithRetry(strings.TrimRight(message, "\n"), "--amend")
+		if err != nil {
+			return fmt.Errorf("amend failed: %w", err)
+		}
+		if !amended {
+			return nil
+		}
+		fmt.Println("✅ Commit amended:", head.Subject)
+		if hash, err := HeadCommit(); err == nil {
+			_ = UpdateTask(task.Branch, func(t *ActiveTask) {
+				t.replaceCommit(head.Hash, hash, head.Subject)
+			})
+		}
+		return nil
+	}
+
+	// Combined diff: the last commit plus whatever is staged now
+	parent := "HEAD~1"
+	if !RefExists(parent) {
+		parent = emptyTreeHash
+	}
+	diff, err := shell.Run("git", "diff", "--cached", parent)
+	if err != nil {
+		return err
+	}
+	summary, _ := shell.Run("git", "diff", "--cached", "--name-status", parent)
+
+	contextForAI := commitContextForAI(diff, summary)
+
+	stop := ui.StartSpinner("Letting the commit gods cook...")
+	commitMsg, err := ai.GenerateCommitMessage(task.Intent, summary, contextForAI)
+	stop()
+	if err != nil {
+		fmt.Println(ui.Red("❌ Failed to generate commit message with AI."))
+		return err
+	}
+	commitMsg = withTaskTrailers(commitMsg, task)
+
+	ui.PrintCommitPlan(ui.CommitPlan{
+		Branch:        task.Branch,
+		Intent:        task.Intent,
+		StagedSummary: summary,
+		CommitMessage: commitMsg,
+	})
+	fmt.Println(ui.Dim("Amending: " + shortHash(head.Hash) + " " + head.Subject))
+
+	if isPushed(head.Hash, task.Branch) {
+		fmt.Println(ui.Yellow("⚠️ This commit is already pushed; you will need to force-push afterwards."))
+	}
+
+	if !ui.Confirm("Amend the last commit?") {
+		fmt.Println("❌ Amend cancelled.")
+		return nil
+	}
+
+	amended, err := commitWithRetry(commitMsg, "--amend")
+	if err != nil {
+		return fmt.Errorf("amend failed: %w", err)
+	}
+	if !amended {
+		return nil
+	}
+	fmt.Println("✅ Commit amended:")
+	fmt.Println(commitMsg)
+
+	if hash, err := HeadCommit(); err == nil {
+		err := UpdateTask(task.Branch, func(t *ActiveTask) {
+			t.replaceCommit(head.Hash, hash, commitMsg)
+		})
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+		}
+	}
+	return nil
+}
+
+// fixupTask lets the user pick an earlier commit on the task branch and
+// records the staged changes as a fixup! commit for it.
+func fixupTask(task *ActiveTask) error {
+	commits, err := taskBranchCommits(task)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Println(ui.Yellow("⚠️ There is no commit on this task branch to fix up."))
+		if taskBaseRef(task.BaseBranch) == "" {
+			fmt.Println(ui.Dim("The task's base branch is unknown, so only commits devgod made for it can be changed."))
+		}
+		return nil
+	}
+
+	if err := prepareIndex(); err != nil {
+		return err
+	}
+	if !HasStagedChanges() {
+		fmt.Println("No staged changes to fold into an earlier commit.")
+		return nil
+	}
+
+	items := make([]string, len(commits))
+	byItem := make(map[string]branchCommit, len(commits))
+	fmt.Println()
+	fmt.Println(ui.Green("Commits on this task:"))
+	for i, c := range commits {
+		items[i] = shortHash(c.Hash) + " " + c.Subject
+		byItem[items[i]] = c
+		fmt.Printf("  %2d) %s\n", i+1, items[i])
+	}
+	fmt.Println()
+
+	choice, err := ui.SelectOne(items, ui.Cyan("Fold the staged changes into which commit?"))
+	if err != nil {
+		return err
+	}
+	target := byItem[choice]
+
+	// Same subject `git commit --fixup` would write
+	message := "fixup! " + target.Subject
+	committed, err := commitWithRetry(message)
+	if err != nil {
+		return fmt.Errorf("fixup commit failed: %w", err)
+	}
+	if !committed {
+		return nil
+	}
+	fmt.Println("✅ Commit created:")
+	fmt.Println(message)
+	fmt.Println(ui.Dim("It will be squashed into " + shortHash(target.Hash) + " when you run: devgod pr"))
+
+	if hash, err := HeadCommit(); err == nil {
+		err := UpdateTask(task.Branch, func(t *ActiveTask) {
+			t.recordCommit(hash, message)
+		})
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+		}
+	}
+	return nil
+}
+
+// isAutosquashSubject reports whether a subject marks a commit for
+// `git rebase --autosquash`.
+func isAutosquashSubject(subject string) bool {
+	return strings.HasPrefix(subject, "fixup! ") || strings.HasPrefix(subject, "squash! ") || strings.HasPrefix(subject, "amend! ")
+}
+
+// countAutosquashCommits counts fixup!/squash!/amend! commits since base.
+func countAutosquashCommits(base string) int {
+	out, err := shell.Run("git", "log", "--format=%s", base+"..HEAD")
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, s := range strings.Split(out, "\n") {
+		if isAutosquashSubject(s) {
+			n++
+		}
+	}
+	return n
+}
+
+// Autosquash folds fixup!/squash! commits into their targets with a
+// non-interactive `git rebase --autosquash` onto the merge base with base.
+// A failed rebase is aborted so the branch is left as it was.
+func Autosquash(base string) error {
+	mergeBase, err := shell.Run("git", "merge-base", base, "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to find merge base with %s: %w", base, err)
+	}
+
+	args := append([]string{"rebase", "-i", "--autosquash", "--autostash"}, signArgs()...)
+	args = append(args, strings.TrimSpace(mergeBase))
+	if planned("GIT_SEQUENCE_EDITOR=true", append([]string{"git"}, args...)...) {
+		return nil
+	}
+	cmd := exec.Command("git", args...)
+	// Accept the todo list as git arranged it
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=true", "GIT_EDITOR=true")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		_, _ = shell.Run("git", "rebase", "--abort")
+		return fmt.Errorf("autosquash rebase failed: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// offerAutosquash squashes pending fixup commits before a PR is prepared.
+// It reports whether history was rewritten.
+func offerAutosquash(baseBranch string) bool {
+	base := taskBaseRef(baseBranch)
+	if base == "" {
+		return false
+	}
+	n := countAutosquashCommits(base)
+	if n == 0 {
+		return false
+	}
+	if !ui.Confirm(fmt.Sprintf("Squash %d fixup commit(s) into their targets first?", n)) {
+		return false
+	}
+	if err := Autosquash(base); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not squash fixup commits; leaving history as it was:"), err)
+		return false
+	}
+	fmt.Println(ui.Green(fmt.Sprintf("✔️ Squashed %d fixup commit(s).", n)))
+	return true
+}
@@ -9,6 +9,15 @@ import (
 	"github.com/jeethsoni/devgod-cli/internal/ui"
 )
 
+// GitHubError reports a failure talking to GitHub through gh, so callers
+// can tell it apart from git or AI problems.
+type GitHubError struct {
+	Err error
+}
+
+func (e *GitHubError) Error() string { return e.Err.Error() }
+func (e *GitHubError) Unwrap() error { return e.Err }
+
 // isGHAuthenticated checks if the user is logged into GitHub CLI.
 // It returns (bool, string) where the string is the raw output from gh.
 func isGHAuthenticated() (bool, string) {
@@ -46,25 +55,25 @@ func ensureGHAuthenticated() error {
 	}
 	fmt.Println()
 
-	if !ui.Confirm("Run `gh auth login` now?") {
+	if !ui.Offer("Run `gh auth login` now?") {
 		fmt.Println(ui.Yellow("To use devgod PR features, please run:"))
 		fmt.Println()
 		fmt.Println("   gh auth login")
 		fmt.Println()
 		fmt.Println("Then re-run `devgod pr`.")
-		return fmt.Errorf("user is not authenticated with GitHub CLI")
+		return &GitHubError{Err: fmt.Errorf("user is not authenticated with GitHub CLI")}
 	}
 
 	// Run `gh auth login` interactively
 	if err := runGHAuthLoginInteractive(); err != nil {
 		fmt.Println(ui.Red("❌ `gh auth login` failed:"))
-		return err
+		return &GitHubError{Err: err}
 	}
 
 	// Re-check auth status after login attempt
 	ok, _ = isGHAuthenticated()
 	if !ok {
-		return fmt.Errorf("GitHub CLI authentication did not complete successfully")
+		return &GitHubError{Err: fmt.Errorf("GitHub CLI authentication did not complete successfully")}
 	}
 
 	fmt.Println(ui.Green("✔️ GitHub CLI authentication complete."))
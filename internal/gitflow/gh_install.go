@@ -58,19 +58,19 @@ func ensureGitHubCLIInstalled() error {
 
 	fmt.Println(ui.Yellow("⚠️ GitHub CLI (gh) is not installed."))
 
-	if !ui.Confirm("Install GitHub CLI now?") {
+	if !ui.Offer("Install GitHub CLI now?") {
 		fmt.Println(ui.Yellow("To use devgod PR features, please install GitHub CLI from:"))
 		fmt.Println()
 		fmt.Println("   https://cli.github.com/")
 		fmt.Println()
 		fmt.Println("Then re-run `devgod pr`.")
-		return fmt.Errorf("GitHub CLI (gh) is required to create PRs")
+		return &GitHubError{Err: fmt.Errorf("GitHub CLI (gh) is required to create PRs")}
 	}
 
 	if err := installGH(); err != nil {
 		fmt.Println(ui.Red("❌ Failed to install GitHub CLI automatically."))
 		fmt.Println(ui.Yellow("Please install it manually from https://cli.github.com/ and try again."))
-		return fmt.Errorf("failed to install GitHub CLI: %w", err)
+		return &GitHubError{Err: fmt.Errorf("failed to install GitHub CLI: %w", err)}
 	}
 
 	fmt.Println(ui.Green("✅ GitHub CLI (gh) installed."))
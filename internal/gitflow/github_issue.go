@@ -0,0 +1,180 @@
+package gitflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// GitHubIssue is the subset of `gh issue view --json` devgod uses.
+type GitHubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// fetchGitHubIssue loads an issue from the current repo via gh.
+func fetchGitHubIssue(number string) (*GitHubIssue, error) {
+	cmd := exec.Command("gh", "issue", "view", number, "--json", "number,title,body,labels,url")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue #%s: %w\n%s", number, err, strings.TrimSpace(string(out)))
+	}
+
+	issue := &GitHubIssue{}
+	if err := json.Unmarshal(out, issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue JSON: %w", err)
+	}
+	return issue, nil
+}
+
+// labelBranchTypes maps words found in labels to a branch type. Labels
+// are matched word by word, so "type: bug" and "kind/bug" count but
+// "debug" and "docker" do not.
+var labelBranchTypes = map[string]string{
+	"bug": "fix", "bugfix": "fix", "defect": "fix", "regression": "fix",
+	"doc": "docs", "docs": "docs", "documentation": "docs",
+	"refactor": "refactor", "refactoring": "refactor", "debt": "refactor",
+	"test": "test", "tests": "test", "testing": "test",
+	"chore": "chore", "dependencies": "chore", "deps": "chore", "maintenance": "chore",
+	"enhancement": "feat", "feature": "feat", "feat": "feat",
+}
+
+// labelWords splits a label into lower-case words.
+func labelWords(label string) []string {
+	return strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// branchTypeForLabels maps common GitHub labels to a branch type.
+// Returns "" when no label gives a clear hint. A bug label wins over any
+// other, since the branch is a fix whatever else it touches.
+func branchTypeForLabels(labels []string) string {
+	found := ""
+	for _, l := range labels {
+		for _, w := range labelWords(l) {
+			switch t := labelBranchTypes[w]; {
+			case t == "fix":
+				return t
+			case t != "" && found == "":
+				found = t
+			}
+		}
+	}
+	return found
+}
+
+// maxIssueContext caps how much of an issue's description goes into the
+// intent, which every AI prompt for the task includes.
+const maxIssueContext = 200
+
+// issueIntent builds a task intent from an issue: its title, followed by
+// the first paragraph of its description for context. Headings, HTML
+// comments and checklists from issue templates are skipped.
+func issueIntent(title, body string) string {
+	intent := strings.Join(strings.Fields(title), " ")
+
+	for _, para := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" || strings.HasPrefix(para, "#") || strings.HasPrefix(para, "<!--") || strings.HasPrefix(para, "- [") {
+			continue
+		}
+		context := []rune(strings.Join(strings.Fields(para), " "))
+		if len(context) > maxIssueContext {
+			context = append(context[:maxIssueContext], '…')
+		}
+		if intent == "" {
+			return string(context)
+		}
+		return intent + ": " + string(context)
+	}
+	return intent
+}
+
+// assignGitHubIssueToSelf assigns the issue to the authenticated gh user.
+func assignGitHubIssueToSelf(number string) error {
+	if planned("gh", "issue", "edit", number, "--add-assignee", "@me") {
+		return nil
+	}
+	cmd := exec.Command("gh", "issue", "edit", number, "--add-assignee", "@me")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// StartTaskFromIssue starts a task seeded from a GitHub issue: the issue
+// title and description become the intent, labels pick the branch type and the PR will
+// close the issue.
+func StartTaskFromIssue(number string, opts StartOptions) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	number = strings.TrimPrefix(strings.TrimSpace(number), "#")
+	if !isGitHubIssueNumber(number) {
+		return fmt.Errorf("invalid issue number %q", number)
+	}
+
+	if err := ensureGitHubCLIInstalled(); err != nil {
+		return err
+	}
+	if err := ensureGHAuthenticated(); err != nil {
+		return err
+	}
+
+	stop := ui.StartSpinner(fmt.Sprintf("Fetching issue #%s...", number))
+	issue, err := fetchGitHubIssue(number)
+	stop()
+	if err != nil {
+		return err
+	}
+
+	var labels []string
+	for _, l := range issue.Labels {
+		labels = append(labels, l.Name)
+	}
+
+	fmt.Println()
+	fmt.Println("🎫 " + ui.SectionTitleStyle.Render(fmt.Sprintf("Issue #%d:", issue.Number)))
+	fmt.Println("   " + ui.ValueStyle.Render(issue.Title))
+	if len(labels) > 0 {
+		fmt.Println("   " + ui.Dim("labels: "+strings.Join(labels, ", ")))
+	}
+	fmt.Println()
+
+	opts.IssueID = fmt.Sprintf("#%d", issue.Number)
+	if opts.BranchType == "" {
+		opts.BranchType = branchTypeForLabels(labels)
+	}
+
+	intent := issueIntent(issue.Title, issue.Body)
+	if validateIntent(intent) != nil {
+		return fmt.Errorf("issue #%d has too little text to start a task from; give the intent yourself: devgod git --issue %d \"<what you will do>\"", issue.Number, issue.Number)
+	}
+
+	started, err := startTask(intent, opts)
+	if err != nil || !started {
+		return err
+	}
+
+	if ui.Offer(fmt.Sprintf("Assign issue #%d to yourself?", issue.Number)) {
+		if err := assignGitHubIssueToSelf(number); err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not assign the issue:"), err)
+		} else {
+			fmt.Println(ui.Green("✔️ Issue assigned to you."))
+		}
+	}
+
+	return nil
+}
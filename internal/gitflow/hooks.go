@@ -0,0 +1,297 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Hooks managed by `devgod hooks install`.
+var devgodHooks = []string{"prepare-commit-msg", "commit-msg"}
+
+// hookMarker identifies hook scripts written by devgod.
+const hookMarker = "# installed by devgod"
+
+// chainedSuffix is appended to a pre-existing hook that devgod wraps.
+const chainedSuffix = ".pre-devgod"
+
+// hooksDir returns the directory git runs hooks from, honouring
+// core.hooksPath.
+func hooksDir() (string, error) {
+	// A relative core.hooksPath is relative to the top of the worktree,
+	// wherever devgod is run from
+	if p := gitConfig("core.hooksPath"); p != "" && !filepath.IsAbs(p) && !strings.HasPrefix(p, "~") {
+		root, err := RepoRoot()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(root, p), nil
+	}
+	return GitPath("hooks")
+}
+
+// isDevgodHook reports whether the hook at path was written by devgod.
+func isDevgodHook(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), hookMarker)
+}
+
+// hookScript renders the shell script for a hook. It runs any chained
+// hook first, then hands over to devgod; a missing devgod binary never
+// blocks a commit.
+func hookScript(name, exe string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s; remove with: devgod hooks uninstall
+hookdir=$(dirname "$0")
+if [ -x "$hookdir/%[2]s%[3]s" ]; then
+	"$hookdir/%[2]s%[3]s" "$@" || exit $?
+fi
+devgod=%[4]s
+[ -x "$devgod" ] || devgod=$(command -v devgod) || exit 0
+exec "$devgod" hook %[2]s "$@"
+`, hookMarker, name, chainedSuffix, quoteArg(exe))
+}
+
+// InstallHooks writes devgod's prepare-commit-msg and commit-msg hooks.
+// Existing hooks are kept and chained so they still run first.
+func InstallHooks() error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "devgod"
+	}
+
+	for _, name := range devgodHooks {
+		path := filepath.Join(dir, name)
+		if plannedAction("install the " + name + " hook at " + path) {
+			continue
+		}
+
+		if _, err := os.Stat(path); err == nil && !isDevgodHook(path) {
+			chained := path + chainedSuffix
+			if _, err := os.Stat(chained); err == nil {
+				return fmt.Errorf("both %s and %s exist; remove one before installing", path, chained)
+			}
+			if err := os.Rename(path, chained); err != nil {
+				return fmt.Errorf("failed to keep existing %s hook: %w", name, err)
+			}
+			fmt.Println(ui.Yellow(fmt.Sprintf("↪️ Existing %s hook kept as %s; it still runs first.", name, filepath.Base(chained))))
+		}
+
+		if err := os.WriteFile(path, []byte(hookScript(name, exe)), 0o755); err != nil {
+			return fmt.Errorf("failed to write %s hook: %w", name, err)
+		}
+		fmt.Println(ui.Green("✔️ Installed:"), path)
+	}
+
+	fmt.Println("Commits made from any tool now get an AI message when none is given, and are checked against the commit format.")
+	return nil
+}
+
+// UninstallHooks removes devgod's hooks and restores any chained ones.
+func UninstallHooks() error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, name := range devgodHooks {
+		path := filepath.Join(dir, name)
+		if !isDevgodHook(path) {
+			continue
+		}
+		if plannedAction("remove the " + name + " hook at " + path) {
+			removed++
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s hook: %w", name, err)
+		}
+		removed++
+		fmt.Println(ui.Green("✔️ Removed:"), path)
+
+		chained := path + chainedSuffix
+		if _, err := os.Stat(chained); err == nil {
+			if err := os.Rename(chained, path); err != nil {
+				return fmt.Errorf("failed to restore the original %s hook: %w", name, err)
+			}
+			fmt.Println(ui.Green("↩️ Restored original"), name, "hook")
+		}
+	}
+
+	if removed == 0 {
+		fmt.Println("No devgod hooks installed.")
+	}
+	return nil
+}
+
+// commentChar returns git's comment character for commit messages.
+func commentChar() string {
+	c := "#"
+	if out, err := shell.Run("git", "config", "--get", "core.commentChar"); err == nil {
+		if v := strings.TrimSpace(out); v != "" && v != "auto" {
+			c = v
+		}
+	}
+	return c
+}
+
+// messageBody returns the commit message without comments and anything
+// below the scissors line.
+func messageBody(msg, comment string) string {
+	var lines []string
+	for _, line := range strings.Split(msg, "\n") {
+		if strings.HasPrefix(line, comment+" ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, comment) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// PrepareCommitMsgHook fills in an AI commit message when the commit was
+// started without one. It never fails the commit: problems are reported
+// and the message is left as git prepared it.
+func PrepareCommitMsgHook(file, source string) error {
+	// Merges, squashes, amends and -m/-F messages already have their text
+	switch source {
+	case "merge", "squash", "commit", "message":
+		return nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	comment := commentChar()
+	if messageBody(string(data), comment) != "" {
+		return nil
+	}
+
+	diff, err := StagedDiff()
+	if err != nil || strings.TrimSpace(diff) == "" {
+		return nil
+	}
+	summary, _ := StagedSummary()
+
+	// Use the task's intent, issue and co-authors when committing on a task branch
+	task := &ActiveTask{}
+	if branch, err := CurrentBranch(); err == nil {
+		if state, err := LoadState(); err == nil {
+			if t := state.TaskForBranch(branch); t != nil {
+				task = t
+			}
+		}
+	}
+
+	msg, err := ai.GenerateCommitMessage(task.Intent, summary, commitContextForAI(diff, summary))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "devgod: could not generate a commit message:", err)
+		return nil
+	}
+
+	// Never put a warning where git would take it as the message
+	if ai.IsWarning(msg) {
+		out := comment + " devgod " + msg + "\n" + string(data)
+		_ = os.WriteFile(file, []byte(out), 0o644)
+		fmt.Fprintln(os.Stderr, "devgod", msg)
+		return nil
+	}
+
+	msg = withTaskTrailers(msg, task)
+	return os.WriteFile(file, []byte(msg+"\n"+string(data)), 0o644)
+}
+
+// Commit types accepted by the commit-msg hook unless overridden by
+// `git config devgod.commit.types`.
+var defaultCommitTypes = []string{"feat", "fix", "chore", "refactor", "docs", "style", "test", "perf", "build", "ci", "revert"}
+
+// Subject length limit unless overridden by `git config devgod.commit.maxlength`.
+const defaultCommitSubjectMax = 72
+
+// ValidateCommitMessage checks a message against the commit format:
+// "<type>(<scope>)!: <description>" on the first line, a blank line before
+// any body. Git-generated merge, revert and fixup subjects are accepted, as
+// are the "wip:" commits devgod makes when starting a task.
+func ValidateCommitMessage(msg string) error {
+	lines := strings.Split(msg, "\n")
+	subject := strings.TrimSpace(lines[0])
+	if subject == "" {
+		return fmt.Errorf("commit message is empty")
+	}
+
+	// Git-generated subjects, and devgod's own WIP commits
+	for _, prefix := range []string{"Merge ", "Revert ", "fixup! ", "squash! ", "amend! ", "wip: "} {
+		if strings.HasPrefix(subject, prefix) {
+			return nil
+		}
+	}
+
+	types := defaultCommitTypes
+	if v := splitList(configValue("commit.types")); len(v) > 0 {
+		types = v
+	}
+	quoted := make([]string, len(types))
+	for i, t := range types {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	re := regexp.MustCompile(`^(` + strings.Join(quoted, "|") + `)(\([^()\s]+\))?!?: \S`)
+	if !re.MatchString(subject) {
+		return fmt.Errorf("subject %q must look like \"<type>: <description>\" with type one of: %s", subject, strings.Join(types, ", "))
+	}
+
+	maxLen := defaultCommitSubjectMax
+	if v := configValue("commit.maxlength"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			maxLen = n
+		}
+	}
+	if n := len([]rune(subject)); n > maxLen {
+		return fmt.Errorf("subject is %d characters; keep it to %d", n, maxLen)
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		return fmt.Errorf("leave a blank line between the subject and the body")
+	}
+	return nil
+}
+
+// CommitMsgHook validates the message git is about to commit.
+func CommitMsgHook(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read commit message: %w", err)
+	}
+	if err := ValidateCommitMessage(messageBody(string(data), commentChar())); err != nil {
+		return fmt.Errorf("commit rejected by devgod: %w (bypass with git commit --no-verify)", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package gitflow
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// isolateGitConfig points git at an empty repo with no global or system
+// config, so only devgod settings a test sets itself apply.
+func isolateGitConfig(t *testing.T) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(t.TempDir())
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+}
+
+func TestValidateCommitMessage(t *testing.T) {
+	isolateGitConfig(t)
+
+	tests := []struct {
+		msg     string
+		wantErr string // "" for a valid message
+	}{
+		{msg: "feat: add csv export"},
+		{msg: "fix(api): handle empty body"},
+		{msg: "feat!: drop the v1 endpoints"},
+		{msg: "refactor(core)!: rename the config keys"},
+		{msg: "feat: add export\n\nLong explanation.\n\nBREAKING CHANGE: new format"},
+		{msg: "Merge branch 'main' into feat/x"},
+		{msg: "fixup! feat: add csv export"},
+		{msg: "wip: save work before starting feat/x"},
+		{msg: "", wantErr: "empty"},
+		{msg: "   \nbody only", wantErr: "empty"},
+		{msg: "add csv export", wantErr: "must look like"},
+		{msg: "feature: add csv export", wantErr: "must look like"},
+		{msg: "feat:add csv export", wantErr: "must look like"},
+		{msg: "feat(): add csv export", wantErr: "must look like"},
+		{msg: "feat: " + strings.Repeat("x", 80), wantErr: "keep it to 72"},
+		{msg: "feat: add export\nno blank line", wantErr: "blank line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidateCommitMessage(tt.msg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ValidateCommitMessage error = %v, want none", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("ValidateCommitMessage error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCommitMessageConfig(t *testing.T) {
+	isolateGitConfig(t)
+	for _, kv := range [][2]string{{"devgod.commit.types", "feat, ops"}, {"devgod.commit.maxlength", "20"}} {
+		if out, err := exec.Command("git", "config", kv[0], kv[1]).CombinedOutput(); err != nil {
+			t.Fatalf("git config: %v\n%s", err, out)
+		}
+	}
+
+	tests := []struct {
+		msg     string
+		wantErr string
+	}{
+		{msg: "ops: rotate keys"},
+		{msg: "fix: rotate keys", wantErr: "one of: feat, ops"},
+		{msg: "feat: a much longer subject", wantErr: "keep it to 20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidateCommitMessage(tt.msg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ValidateCommitMessage error = %v, want none", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("ValidateCommitMessage error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+var (
+	// Tracker keys such as ABC-123 or JIRA-452.
+	trackerIssueRe = regexp.MustCompile(`\b([A-Z][A-Z0-9]+-\d+)\b`)
+	// GitHub-style references such as #42.
+	githubIssueRe = regexp.MustCompile(`(?:^|[\s(])#(\d+)\b`)
+)
+
+// notIssueKeys are prefixes of standards and versions that look like
+// tracker keys but are not, e.g. UTF-8 or SHA-256.
+var notIssueKeys = map[string]bool{
+	"AES": true, "CP": true, "CVE": true, "ECMA": true, "ES": true,
+	"HTTP": true, "IEEE": true, "IPV": true, "ISO": true, "MD": true,
+	"PEP": true, "RFC": true, "RSA": true, "SHA": true, "SSL": true,
+	"TLS": true, "UCS": true, "UTF": true,
+}
+
+// issueProject returns the project part of a tracker key: "ABC" for
+// "ABC-123".
+func issueProject(key string) string {
+	project, _, _ := strings.Cut(key, "-")
+	return project
+}
+
+// trackerProjects returns the project keys listed in
+// DEVGOD_TRACKER_PROJECTS, upper-cased. Empty means any project.
+func trackerProjects() []string {
+	var projects []string
+	for _, p := range splitList(os.Getenv("DEVGOD_TRACKER_PROJECTS")) {
+		projects = append(projects, strings.ToUpper(p))
+	}
+	return projects
+}
+
+// DetectIssueID returns the first issue reference typed in the intent,
+// e.g. "ABC-123" or "#42". Returns "" when there is none.
+func DetectIssueID(intent string) string {
+	for _, m := range trackerIssueRe.FindAllStringSubmatch(intent, -1) {
+		if !notIssueKeys[issueProject(m[1])] {
+			return m[1]
+		}
+	}
+	if m := githubIssueRe.FindStringSubmatch(intent); m != nil {
+		return "#" + m[1]
+	}
+	return ""
+}
+
+// confirmDetectedIssue decides whether an issue ID found in the intent
+// belongs to the task. GitHub references and keys of the configured
+// tracker projects are taken as is; any other key is only used once the
+// user confirms it, since it may just be a term like "HTTP-2".
+func confirmDetectedIssue(id string) string {
+	if id == "" {
+		return ""
+	}
+	if !isGitHubIssue(id) {
+		projects := trackerProjects()
+		switch {
+		case len(projects) > 0 && !slices.Contains(projects, issueProject(id)):
+			fmt.Println(ui.Dim(fmt.Sprintf("%s is not in DEVGOD_TRACKER_PROJECTS; not treating it as a ticket.", id)))
+			return ""
+		case len(projects) == 0 && !ui.Offer(fmt.Sprintf("Is %s the ticket for this task?", id)):
+			return ""
+		}
+	}
+	fmt.Println(ui.Green("✔️ Detected issue:"), id)
+	return id
+}
+
+// normalizeIssueID cleans a user-supplied issue ID. Bare numbers are treated
+// as GitHub issues ("42" -> "#42"); tracker keys are upper-cased.
+func normalizeIssueID(id string) string {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ""
+	}
+
+	if isGitHubIssueNumber(strings.TrimPrefix(id, "#")) {
+		return "#" + strings.TrimPrefix(id, "#")
+	}
+	return strings.ToUpper(id)
+}
+
+func isGitHubIssueNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isGitHubIssue reports whether the normalized ID refers to a GitHub issue.
+func isGitHubIssue(id string) bool {
+	return strings.HasPrefix(id, "#") && isGitHubIssueNumber(id[1:])
+}
+
+// branchIssueID returns the form of the issue ID used inside branch names.
+// "#42" becomes "42" since '#' is awkward in refs and shells.
+func branchIssueID(id string) string {
+	return strings.TrimPrefix(id, "#")
+}
+
+// withCommitIssueRef appends a "Refs <id>" trailer to a commit message.
+func withCommitIssueRef(message, id string) string {
+	if id == "" || ai.IsWarning(message) {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\nRefs " + id
+}
+
+// withPRIssueRef appends a closing keyword for GitHub issues, or a plain
+// reference for tracker keys, to a PR body.
+func withPRIssueRef(body, id string) string {
+	if id == "" {
+		return body
+	}
+
+	line := "Refs " + id
+	if isGitHubIssue(id) {
+		line = "Closes " + id
+	}
+
+	if strings.Contains(body, line) {
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + line
+}
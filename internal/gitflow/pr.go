@@ -17,9 +17,11 @@ type PRSizeStats struct {
 	LinesDeleted int
 }
 
-// PRSize computes how many files and lines changed between base and head.
+// PRSize computes how many files and lines head changes since it forked
+// from base. It diffs from the merge base (base...head) so commits that
+// landed on base in the meantime are not counted.
 func PRSize(baseBranch, headBranch string) (*PRSizeStats, error) {
-	cmd := exec.Command("git", "diff", "--numstat", fmt.Sprintf("%s..%s", baseBranch, headBranch))
+	cmd := exec.Command("git", "diff", "--numstat", fmt.Sprintf("%s...%s", baseBranch, headBranch))
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to run git diff --numstat: %w", err)
@@ -62,10 +64,10 @@ func parseNumstatField(val string) int {
 	return n
 }
 
-// DiffSummary returns a name-status summary between base and head,
-// similar to "git diff --name-status base..head".
+// DiffSummary returns a name-status summary of what head changes since it
+// forked from base, similar to "git diff --name-status base...head".
 func DiffSummary(baseBranch, headBranch string) (string, error) {
-	cmd := exec.Command("git", "diff", "--name-status", fmt.Sprintf("%s..%s", baseBranch, headBranch))
+	cmd := exec.Command("git", "diff", "--name-status", fmt.Sprintf("%s...%s", baseBranch, headBranch))
 	out, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to run git diff --name-status: %w", err)
@@ -73,41 +75,97 @@ func DiffSummary(baseBranch, headBranch string) (string, error) {
 	return string(out), nil
 }
 
+// PROptions holds optional inputs for CreatePR. Each one replaces a prompt.
+type PROptions struct {
+	// Base is the branch to open the PR against.
+	Base string
+
+	// Reviewers are GitHub logins to request reviews from.
+	Reviewers []string
+
+	// Template is the path of the PR template to fill, or "none".
+	Template string
+}
+
+// missingInputs lists what CreatePR would have to ask for that can only
+// come from flags when stdin is not a terminal.
+func (opts PROptions) missingInputs(task *ActiveTask) []string {
+	var missing []string
+	if !ui.AssumeYes() {
+		missing = append(missing, "--yes to create the PR without confirming")
+	}
+	if opts.Base == "" && task.BaseBranch == "" {
+		missing = append(missing, "--base <branch>, since the task has no recorded base branch")
+	}
+	if opts.Template == "" {
+		if templates, _ := findPRTemplates(); len(templates) > 1 {
+			missing = append(missing, "--template <path|none>, since the repo has several PR templates")
+		}
+	}
+	return missing
+}
+
 // CreatePR generates PR metadata and creates a GitHub PR using gh.
-func CreatePR() error {
+func CreatePR(opts PROptions) error {
 	if !IsGitRepo() {
 		return fmt.Errorf("not inside a git repo")
 	}
 
-	// Ensure gh is present and authenticated
-	if err := ensureGitHubCLIInstalled(); err != nil {
-		return err
-	}
-	if err := ensureGHAuthenticated(); err != nil {
-		return err
-	}
-
 	// Load devgod state to get intent + branch
 	state, err := LoadState()
 	if err != nil {
 		return err
 	}
-	if state.ActiveTask == nil {
-		return fmt.Errorf("no active task found. Run `devgod git \"your intent\"` first")
-	}
 
 	branch, err := CurrentBranch()
 	if err != nil {
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	baseBranch, err := selectBaseBranchInteractive()
-	if err != nil {
-		return fmt.Errorf("failed to choose base branch: %w", err)
+	task := state.TaskForBranch(branch)
+	if task == nil {
+		return fmt.Errorf("no task found for branch %s. Run `devgod git \"your intent\"` or `devgod tasks switch` first", branch)
+	}
+
+	if !ui.Interactive() {
+		if missing := opts.missingInputs(task); len(missing) > 0 {
+			return &ui.MissingInputError{Inputs: missing}
+		}
+	}
+
+	// Ensure gh is present and authenticated
+	if err := ensureGitHubCLIInstalled(); err != nil {
+		return err
+	}
+	if err := ensureGHAuthenticated(); err != nil {
+		return err
+	}
+
+	// Default to the base the task was started from
+	baseBranch := opts.Base
+	if baseBranch != "" {
+		fmt.Println(ui.Green("✔️ Base branch:"), baseBranch)
+	} else if task.BaseBranch != "" && ui.Confirm(fmt.Sprintf("Open the PR against %s?", task.BaseBranch)) {
+		baseBranch = task.BaseBranch
+	} else {
+		baseBranch, err = selectBaseBranchInteractive()
+		if err != nil {
+			return fmt.Errorf("failed to choose base branch: %w", err)
+		}
+	}
+
+	// Fold fixup! commits into their targets before describing the branch
+	rewritten := offerAutosquash(baseBranch)
+
+	// Compare against origin's copy of the base when we have one; the local
+	// branch may be stale
+	compareRef := taskBaseRef(baseBranch)
+	if compareRef == "" {
+		compareRef = baseBranch
 	}
 
 	// Compute PR size stats
-	stats, err := PRSize(baseBranch, branch)
+	stats, err := PRSize(compareRef, branch)
 	if err != nil {
 		return fmt.Errorf("failed to compute PR size: %w", err)
 	}
@@ -148,23 +206,43 @@ func CreatePR() error {
 	}
 
 	// Build context for AI (summary keeps it concise)
-	summary, err := DiffSummary(baseBranch, branch)
+	summary, err := DiffSummary(compareRef, branch)
 	if err != nil {
 		return fmt.Errorf("failed to compute diff summary: %w", err)
 	}
 
+	// Fill the repo's PR template when it has one
+	var template *PRTemplate
+	if opts.Template != "" {
+		template, err = findPRTemplate(opts.Template)
+	} else {
+		template, err = selectPRTemplateInteractive()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to choose PR template: %w", err)
+	}
+
 	// Ask AI for PR title + body
 	stop := ui.StartSpinner("🪄 Asking the PR gods to write your title & description...")
-	meta, err := ai.GeneratePRMetadata(state.ActiveTask.Intent, summary, branch, baseBranch)
+	var meta *ai.PRMetadata
+	if template != nil {
+		meta, err = ai.GeneratePRFromTemplate(task.Intent, summary, branch, baseBranch, template.Content)
+	} else {
+		meta, err = ai.GeneratePRMetadata(task.Intent, summary, branch, baseBranch)
+	}
 	stop()
 	if err != nil {
 		return fmt.Errorf("failed to generate PR metadata with AI: %w", err)
 	}
-
-	// Reviewers selection
-	reviewers, err := getReviewersOrAsk()
-	if err != nil {
-		return fmt.Errorf("failed to select reviewers: %w", err)
+	meta.Body = withPRIssueRef(meta.Body, task.IssueID)
+
+	// Reviewers selection; unattended runs only request the ones given
+	reviewers := opts.Reviewers
+	if len(reviewers) == 0 && ui.Interactive() && !ui.AssumeYes() {
+		reviewers, err = getReviewersOrAsk()
+		if err != nil {
+			return fmt.Errorf("failed to select reviewers: %w", err)
+		}
 	}
 	// Show a preview before hitting GitHub
 	fmt.Println()
@@ -214,27 +292,64 @@ func CreatePR() error {
 		fmt.Println("❌ PR creation cancelled.")
 		return nil
 	}
-	if !IsBranchPushed(branch) {
+	if rewritten && IsBranchPushed(branch) {
+		fmt.Println(ui.Yellow("Force-pushing the squashed branch to origin..."))
+
+		if err := ForcePushBranch(branch); err != nil {
+			return fmt.Errorf("failed to push branch: %w", err)
+		}
+
+		if !dryRun {
+			fmt.Println(ui.Green("✔️ Branch pushed to origin."))
+		}
+		fmt.Println()
+	} else if !IsBranchPushed(branch) {
 		fmt.Println(ui.Yellow("Pushing branch to origin..."))
 
 		if err := PushBranch(branch); err != nil {
 			return fmt.Errorf("failed to push branch: %w", err)
 		}
 
-		fmt.Println(ui.Green("✔️ Branch pushed to origin."))
+		if !dryRun {
+			fmt.Println(ui.Green("✔️ Branch pushed to origin."))
+		}
 		fmt.Println()
 	}
 
 	// Call gh to actually create the PR
-	if err := createGitHubPR(baseBranch, meta.Title, meta.Body, reviewers); err != nil {
+	prURL, err := createGitHubPR(baseBranch, meta.Title, meta.Body, reviewers)
+	if err != nil {
 		return fmt.Errorf("failed to create PR on GitHub: %w", err)
 	}
 
-	fmt.Println(ui.Green("✅ PR created successfully on GitHub."))
+	if !dryRun {
+		fmt.Println(ui.Green("✅ PR created successfully on GitHub."))
+	}
+	if prURL != "" {
+		fmt.Println("   " + prURL)
+	}
+
+	// Record the PR on the task
+	err = UpdateTask(branch, func(t *ActiveTask) {
+		t.PRURL = prURL
+		t.PRNumber = prNumberFromURL(prURL)
+		t.PRState = "OPEN"
+		if t.BaseBranch == "" {
+			t.BaseBranch = baseBranch
+		}
+		t.touch()
+	})
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+	}
+
+	markTicketInReview(task.IssueID, prURL)
+	offerFinishedWorktreeRemoval(task)
 	return nil
 }
 
-func createGitHubPR(baseBranch, title, body string, reviewers []string) error {
+// createGitHubPR runs `gh pr create` and returns the URL of the new PR.
+func createGitHubPR(baseBranch, title, body string, reviewers []string) (string, error) {
 	args := []string{
 		"pr", "create",
 		"--title", title,
@@ -252,6 +367,10 @@ func createGitHubPR(baseBranch, title, body string, reviewers []string) error {
 		}
 	}
 
+	if planned("gh", args...) {
+		return "", nil
+	}
+
 	cmd := exec.Command("gh", args...)
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -259,8 +378,28 @@ func createGitHubPR(baseBranch, title, body string, reviewers []string) error {
 
 	if err := cmd.Run(); err != nil {
 		fmt.Println(out.String())
-		return err
+		return "", &GitHubError{Err: err}
 	}
 
-	return nil
+	// gh prints the PR URL as the last line of its output.
+	prURL := ""
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "https://") {
+			prURL = strings.TrimSpace(line)
+		}
+	}
+	return prURL, nil
+}
+
+// prNumberFromURL extracts 42 from https://github.com/owner/repo/pull/42.
+func prNumberFromURL(prURL string) int {
+	idx := strings.LastIndex(prURL, "/pull/")
+	if idx == -1 {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.Trim(prURL[idx+len("/pull/"):], "/"))
+	if err != nil {
+		return 0
+	}
+	return n
 }
@@ -0,0 +1,143 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// PRTemplate is a pull request template found in the repository.
+type PRTemplate struct {
+	// Path relative to the repo root, e.g. ".github/pull_request_template.md".
+	Path    string
+	Content string
+}
+
+// prTemplateDirs are the locations GitHub looks for PR templates.
+var prTemplateDirs = []string{".github", "", "docs"}
+
+const noTemplateOption = "(none) free-form description"
+
+// findPRTemplates returns every PR template in the standard GitHub locations,
+// including multiple templates under a PULL_REQUEST_TEMPLATE/ directory.
+func findPRTemplates() ([]PRTemplate, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []PRTemplate
+	for _, dir := range prTemplateDirs {
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			name := strings.ToLower(e.Name())
+
+			switch {
+			case !e.IsDir() && (name == "pull_request_template.md" || name == "pull_request_template.txt"):
+				if t, ok := readPRTemplate(root, filepath.Join(dir, e.Name())); ok {
+					templates = append(templates, t)
+				}
+
+			case e.IsDir() && name == "pull_request_template":
+				sub := filepath.Join(dir, e.Name())
+				files, err := os.ReadDir(filepath.Join(root, sub))
+				if err != nil {
+					continue
+				}
+				for _, f := range files {
+					ext := strings.ToLower(filepath.Ext(f.Name()))
+					if f.IsDir() || (ext != ".md" && ext != ".txt") {
+						continue
+					}
+					if t, ok := readPRTemplate(root, filepath.Join(sub, f.Name())); ok {
+						templates = append(templates, t)
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].Path < templates[j].Path
+	})
+	return templates, nil
+}
+
+func readPRTemplate(root, rel string) (PRTemplate, bool) {
+	data, err := os.ReadFile(filepath.Join(root, rel))
+	if err != nil || strings.TrimSpace(string(data)) == "" {
+		return PRTemplate{}, false
+	}
+	return PRTemplate{Path: filepath.ToSlash(rel), Content: string(data)}, true
+}
+
+// findPRTemplate returns the template at path (relative to the repo root),
+// or nil for "none".
+func findPRTemplate(path string) (*PRTemplate, error) {
+	if path == "none" {
+		return nil, nil
+	}
+	templates, err := findPRTemplates()
+	if err != nil {
+		return nil, err
+	}
+	want := filepath.ToSlash(filepath.Clean(path))
+	for i := range templates {
+		if templates[i].Path == want {
+			fmt.Println(ui.Green("✔️ PR template:"), templates[i].Path)
+			return &templates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no PR template at %s", path)
+}
+
+// selectPRTemplateInteractive lets the user choose which PR template to fill.
+// Returns nil when the repo has no templates or the user opts out.
+func selectPRTemplateInteractive() (*PRTemplate, error) {
+	templates, err := findPRTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(templates) {
+	case 0:
+		return nil, nil
+	case 1:
+		if ui.Confirm(fmt.Sprintf("Fill the PR template %s?", templates[0].Path)) {
+			return &templates[0], nil
+		}
+		return nil, nil
+	}
+
+	options := make([]string, 0, len(templates)+1)
+	fmt.Println()
+	fmt.Println(ui.Green("PR templates found in this repo:"))
+	for i, t := range templates {
+		options = append(options, t.Path)
+		fmt.Printf("  %2d) %s\n", i+1, t.Path)
+	}
+	options = append(options, noTemplateOption)
+	fmt.Printf("  %2d) %s\n", len(options), noTemplateOption)
+	fmt.Println()
+
+	selected, err := ui.SelectOne(options, ui.Cyan("Select PR template by number:"))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		if templates[i].Path == selected {
+			fmt.Println(ui.Green("✔️ PR template:"), selected)
+			return &templates[i], nil
+		}
+	}
+	return nil, nil
+}
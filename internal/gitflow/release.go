@@ -0,0 +1,384 @@
+package gitflow
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Bump levels, ordered from smallest to largest.
+const (
+	BumpPatch = "patch"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+var (
+	semverTagRe      = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)$`)
+	conventionalRe   = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?(!)?:\s*(.+)$`)
+	breakingFooterRe = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+)
+
+// Version is a parsed semantic version tag like "v1.4.2".
+type Version struct {
+	Prefix string
+	Major  int
+	Minor  int
+	Patch  int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+}
+
+// Bump returns the next version for the given bump level.
+func (v Version) Bump(level string) Version {
+	switch level {
+	case BumpMajor:
+		return Version{Prefix: v.Prefix, Major: v.Major + 1}
+	case BumpMinor:
+		return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+// Less reports whether v is an earlier version than o, ignoring prefixes.
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+// ParseVersion parses a semver tag such as "1.2.3" or "v1.2.3".
+func ParseVersion(tag string) (Version, bool) {
+	m := semverTagRe.FindStringSubmatch(strings.TrimSpace(tag))
+	if m == nil {
+		return Version{}, false
+	}
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	patch, _ := strconv.Atoi(m[4])
+	return Version{Prefix: m[1], Major: major, Minor: minor, Patch: patch}, true
+}
+
+// ReleaseCommit is a single commit considered for the next release.
+type ReleaseCommit struct {
+	Hash     string
+	Type     string
+	Subject  string
+	Breaking bool
+}
+
+// LatestSemverTag returns the highest semver tag reachable from HEAD.
+// found is false when the repo has no semver tags yet.
+func LatestSemverTag() (tag string, version Version, found bool, err error) {
+	out, err := shell.Run("git", "tag", "--merged", "HEAD", "--sort=-v:refname")
+	if err != nil {
+		return "", Version{}, false, err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if v, ok := ParseVersion(line); ok {
+			return line, v, true, nil
+		}
+	}
+	return "", Version{}, false, nil
+}
+
+// semverTags returns every semver tag in the repo, on any branch.
+func semverTags() (map[string]Version, error) {
+	out, err := shell.Run("git", "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]Version{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if v, ok := ParseVersion(line); ok {
+			tags[line] = v
+		}
+	}
+	return tags, nil
+}
+
+// checkNextVersion refuses a version that is already tagged somewhere,
+// such as a hotfix tag on another branch that HEAD does not contain, and
+// warns when a higher version exists elsewhere.
+func checkNextVersion(next Version) error {
+	tags, err := semverTags()
+	if err != nil {
+		return fmt.Errorf("failed to read tags: %w", err)
+	}
+
+	highest, highestTag := Version{}, ""
+	for tag, v := range tags {
+		if !v.Less(next) && !next.Less(v) {
+			return fmt.Errorf("version %s is already tagged as %s on another branch; merge that branch in or pass --bump for a different version", next, tag)
+		}
+		if highestTag == "" || highest.Less(v) {
+			highest, highestTag = v, tag
+		}
+	}
+	if highestTag != "" && next.Less(highest) {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ %s already exists on another branch; %s will be lower than it.", highestTag, next)))
+	}
+	return nil
+}
+
+// CommitsSince returns the commits after the given tag (or all commits if
+// tag is empty), oldest first, parsed as conventional commits.
+func CommitsSince(tag string) ([]ReleaseCommit, error) {
+	args := []string{"log", "--reverse", "--format=%h%x1f%s%x1f%b%x1e"}
+	if tag != "" {
+		args = append(args, tag+"..HEAD")
+	}
+
+	out, err := shell.Run("git", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []ReleaseCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 3)
+		if len(fields) < 2 {
+			continue
+		}
+
+		body := ""
+		if len(fields) == 3 {
+			body = fields[2]
+		}
+		commits = append(commits, parseReleaseCommit(fields[0], fields[1], body))
+	}
+	return commits, nil
+}
+
+func parseReleaseCommit(hash, subject, body string) ReleaseCommit {
+	c := ReleaseCommit{
+		Hash:     strings.TrimSpace(hash),
+		Subject:  strings.TrimSpace(subject),
+		Breaking: breakingFooterRe.MatchString(body),
+	}
+
+	if m := conventionalRe.FindStringSubmatch(c.Subject); m != nil {
+		c.Type = strings.ToLower(m[1])
+		c.Subject = strings.TrimSpace(m[4])
+		if m[3] == "!" {
+			c.Breaking = true
+		}
+	}
+	return c
+}
+
+// SuggestBump returns the bump level implied by the commits:
+// breaking changes -> major, feat -> minor, everything else -> patch.
+func SuggestBump(commits []ReleaseCommit) string {
+	level := BumpPatch
+	for _, c := range commits {
+		if c.Breaking {
+			return BumpMajor
+		}
+		if c.Type == "feat" {
+			level = BumpMinor
+		}
+	}
+	return level
+}
+
+// ReleaseNotes renders markdown release notes grouped by change type.
+func ReleaseNotes(commits []ReleaseCommit) string {
+	var breaking, feats, fixes, other []string
+	for _, c := range commits {
+		line := fmt.Sprintf("- %s (%s)", c.Subject, c.Hash)
+		if c.Breaking {
+			breaking = append(breaking, line)
+			continue
+		}
+		switch c.Type {
+		case "feat":
+			feats = append(feats, line)
+		case "fix":
+			fixes = append(fixes, line)
+		default:
+			other = append(other, line)
+		}
+	}
+
+	var b strings.Builder
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## " + title + "\n")
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n")
+	}
+
+	section("Breaking Changes", breaking)
+	section("Features", feats)
+	section("Fixes", fixes)
+	section("Other Changes", other)
+
+	return strings.TrimSpace(b.String())
+}
+
+// CreateAnnotatedTag creates an annotated tag on HEAD with the given message.
+func CreateAnnotatedTag(tag, message string) error {
+	_, err := mutate("git", "tag", "-a", tag, "-m", message)
+	return err
+}
+
+// PushTag pushes a single tag to origin.
+func PushTag(tag string) error {
+	_, err := mutate("git", "push", "origin", tag)
+	return err
+}
+
+func createGitHubRelease(tag, notes string) error {
+	if planned("gh", "release", "create", tag, "--title", tag, "--notes", notes, "--verify-tag") {
+		return nil
+	}
+	cmd := exec.Command("gh", "release", "create", tag, "--title", tag, "--notes", notes, "--verify-tag")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Println(string(out))
+		return err
+	}
+	return nil
+}
+
+// Release proposes the next semantic version from commits since the latest
+// tag, creates an annotated tag and optionally publishes a GitHub release.
+// bump overrides the suggested level when non-empty.
+func Release(bump string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	switch bump {
+	case "", BumpMajor, BumpMinor, BumpPatch:
+	default:
+		return fmt.Errorf("invalid bump %q; use major, minor or patch", bump)
+	}
+
+	if !RefExists("HEAD") {
+		fmt.Println("No commits yet. Nothing to release.")
+		return nil
+	}
+
+	latestTag, current, found, err := LatestSemverTag()
+	if err != nil {
+		return fmt.Errorf("failed to read tags: %w", err)
+	}
+	if !found {
+		current = Version{Prefix: "v"}
+	}
+
+	commits, err := CommitsSince(latestTag)
+	if err != nil {
+		return fmt.Errorf("failed to read commits: %w", err)
+	}
+	if len(commits) == 0 {
+		if found {
+			fmt.Println("No commits since", latestTag+". Nothing to release.")
+		} else {
+			fmt.Println("No commits yet. Nothing to release.")
+		}
+		return nil
+	}
+
+	suggested := SuggestBump(commits)
+	if bump == "" {
+		bump = suggested
+	}
+	next := current.Bump(bump)
+	if err := checkNextVersion(next); err != nil {
+		return err
+	}
+	notes := ReleaseNotes(commits)
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("🏷️  DEVGOD RELEASE PREVIEW"))
+
+	fmt.Println("📌 " + ui.SectionTitleStyle.Render("Current version:"))
+	if found {
+		fmt.Println("   " + ui.ValueStyle.Render(latestTag))
+	} else {
+		fmt.Println("   " + ui.ValueStyle.Render("(no tags yet)"))
+	}
+	fmt.Println()
+
+	fmt.Println("🚀 " + ui.SectionTitleStyle.Render("Next version:"))
+	fmt.Printf("   %s (%s bump, suggested: %s)\n", ui.ValueStyle.Render(next.String()), bump, suggested)
+	fmt.Println()
+
+	fmt.Println("📄 " + ui.SectionTitleStyle.Render("Release notes:"))
+	for _, line := range strings.Split(notes, "\n") {
+		if strings.TrimSpace(line) == "" {
+			fmt.Println()
+			continue
+		}
+		fmt.Println("   " + ui.ValueStyle.Render(line))
+	}
+	fmt.Println()
+
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+	fmt.Println()
+
+	if !ui.Confirm(fmt.Sprintf("Create annotated tag %s?", next)) {
+		fmt.Println("❌ Release cancelled.")
+		return nil
+	}
+
+	if err := CreateAnnotatedTag(next.String(), next.String()+"\n\n"+notes); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	if !dryRun {
+		fmt.Println(ui.Green("✔️ Tag created:"), next)
+	}
+
+	if !ui.Confirm("Push the tag and publish a GitHub release?") {
+		fmt.Println("Tag kept locally. Push it later with:")
+		fmt.Println("  git push origin", next)
+		return nil
+	}
+
+	if err := ensureGitHubCLIInstalled(); err != nil {
+		return err
+	}
+	if err := ensureGHAuthenticated(); err != nil {
+		return err
+	}
+
+	if err := PushTag(next.String()); err != nil {
+		return fmt.Errorf("failed to push tag: %w", err)
+	}
+
+	if err := createGitHubRelease(next.String(), notes); err != nil {
+		return fmt.Errorf("failed to create GitHub release: %w", err)
+	}
+
+	if !dryRun {
+		fmt.Println(ui.Green("✅ GitHub release published:"), next)
+	}
+	return nil
+}
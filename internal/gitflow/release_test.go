@@ -0,0 +1,123 @@
+package gitflow
+
+import "testing"
+
+func TestParseReleaseCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		want    ReleaseCommit
+	}{
+		{
+			name:    "feat",
+			subject: "feat: add csv export",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "feat", Subject: "add csv export"},
+		},
+		{
+			name:    "scoped fix",
+			subject: "fix(api): handle empty body",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "fix", Subject: "handle empty body"},
+		},
+		{
+			name:    "bang marks breaking",
+			subject: "feat!: drop the v1 endpoints",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "feat", Subject: "drop the v1 endpoints", Breaking: true},
+		},
+		{
+			name:    "scoped bang",
+			subject: "refactor(core)!: rename config keys",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "refactor", Subject: "rename config keys", Breaking: true},
+		},
+		{
+			name:    "breaking footer",
+			subject: "fix: new token format",
+			body:    "Tokens are longer now.\n\nBREAKING CHANGE: old tokens stop working",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "fix", Subject: "new token format", Breaking: true},
+		},
+		{
+			name:    "hyphenated footer",
+			subject: "chore: bump deps",
+			body:    "BREAKING-CHANGE: needs Go 1.25",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "chore", Subject: "bump deps", Breaking: true},
+		},
+		{
+			name:    "footer mentioned mid-line is not breaking",
+			subject: "docs: explain releases",
+			body:    "A BREAKING CHANGE: footer bumps the major version.",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "docs", Subject: "explain releases"},
+		},
+		{
+			name:    "type is lowercased",
+			subject: "Feat: add export",
+			want:    ReleaseCommit{Hash: "abc1234", Type: "feat", Subject: "add export"},
+		},
+		{
+			name:    "not conventional",
+			subject: "Update README.md",
+			want:    ReleaseCommit{Hash: "abc1234", Subject: "Update README.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseReleaseCommit(" abc1234\n", tt.subject, tt.body)
+			if got != tt.want {
+				t.Errorf("parseReleaseCommit = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestBump(t *testing.T) {
+	fix := ReleaseCommit{Type: "fix"}
+	feat := ReleaseCommit{Type: "feat"}
+	chore := ReleaseCommit{Type: "chore"}
+	breaking := ReleaseCommit{Type: "fix", Breaking: true}
+
+	tests := []struct {
+		name    string
+		commits []ReleaseCommit
+		want    string
+	}{
+		{name: "no commits", want: BumpPatch},
+		{name: "fixes and chores", commits: []ReleaseCommit{fix, chore}, want: BumpPatch},
+		{name: "a feature", commits: []ReleaseCommit{fix, feat, chore}, want: BumpMinor},
+		{name: "breaking fix", commits: []ReleaseCommit{feat, breaking}, want: BumpMajor},
+		{name: "breaking first", commits: []ReleaseCommit{breaking, feat}, want: BumpMajor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestBump(tt.commits); got != tt.want {
+				t.Errorf("SuggestBump = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	v, ok := ParseVersion("v1.4.2")
+	if !ok {
+		t.Fatal("ParseVersion(v1.4.2) failed")
+	}
+	tests := []struct {
+		level string
+		want  string
+	}{
+		{level: BumpPatch, want: "v1.4.3"},
+		{level: BumpMinor, want: "v1.5.0"},
+		{level: BumpMajor, want: "v2.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := v.Bump(tt.level).String(); got != tt.want {
+				t.Errorf("Bump(%s) = %s, want %s", tt.level, got, tt.want)
+			}
+		})
+	}
+
+	for _, tag := range []string{"1.2", "v1.2.3-rc1", "release-1.2.3"} {
+		if _, ok := ParseVersion(tag); ok {
+			t.Errorf("ParseVersion(%q) succeeded, want failure", tag)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/jeethsoni/devgod-cli/internal/shell"
@@ -18,6 +20,47 @@ func RepoRoot() (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// Returns the absolute path to the .git directory shared by all worktrees.
+// In a linked worktree `.git` is a file, so this must not be derived from
+// RepoRoot.
+func GitCommonDir() (string, error) {
+	out, err := shell.Run("git", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err == nil {
+		return strings.TrimSpace(out), nil
+	}
+
+	// Older git without --path-format: resolve the (possibly relative) path.
+	out, err = shell.Run("git", "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(out)
+	if !filepath.IsAbs(dir) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(wd, dir)
+	}
+	return dir, nil
+}
+
+// Returns the absolute path of a file inside the .git directory, such as
+// "index" or "hooks", as git resolves it for this worktree.
+func GitPath(name string) (string, error) {
+	out, err := shell.Run("git", "rev-parse", "--path-format=absolute", "--git-path", name)
+	if err == nil {
+		return strings.TrimSpace(out), nil
+	}
+
+	// Older git: the path is relative to the current directory, not the root.
+	out, err = shell.Run("git", "rev-parse", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(out))
+}
+
 // Returns true if the current directory is inside a git repo.
 func IsGitRepo() bool {
 	_, err := RepoRoot()
@@ -30,22 +73,43 @@ func CurrentBranch() (string, error) {
 	return strings.TrimSpace(out), err
 }
 
-// Checks out a new branch with the given name.
-func CheckoutNewBranch(name string) error {
-	_, err := shell.Run("git", "checkout", "-b", name)
+// Checks out a new branch with the given name, starting at startPoint
+// (or HEAD when startPoint is empty). The branch never tracks startPoint,
+// so a later push creates its own remote branch.
+func CheckoutNewBranch(name, startPoint string) error {
+	args := []string{"checkout", "--no-track", "-b", name}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	_, err := mutate("git", args...)
 	return err
 }
 
-// Stages all changes in the working directory.
+// Returns true if a remote with the given name is configured.
+func HasRemote(name string) bool {
+	_, err := shell.Run("git", "remote", "get-url", name)
+	return err == nil
+}
+
+// Stages all changes in the working tree, including untracked files,
+// regardless of the current directory.
 func StageAll() error {
-	_, err := shell.Run("git", "add", ".")
+	announce("git", "add", "-A")
+	_, err := shell.Run("git", "add", "-A")
+	return err
+}
+
+// Stages the given paths (additions, modifications and deletions).
+func StageFiles(paths []string) error {
+	args := append([]string{"add", "-A", "--"}, paths...)
+	announce("git", args...)
+	_, err := shell.Run("git", args...)
 	return err
 }
 
 // Commits staged changes with the given message.
 func Commit(message string) error {
-	_, err := shell.Run("git", "commit", "-m", message)
-	return err
+	return commit(message)
 }
 
 // Returns true if there are unstaged changes in the working directory.
@@ -54,19 +118,119 @@ func HasUnstagedChanges() bool {
 	return strings.TrimSpace(out) != ""
 }
 
+// Returns true if tracked files have staged or unstaged changes. Untracked
+// files are ignored, since resets and rebases leave them alone.
+func HasTrackedChanges() bool {
+	out, _ := shell.Run("git", "status", "--porcelain", "--untracked-files=no")
+	return strings.TrimSpace(out) != ""
+}
+
 // Returns the diff of staged changes.
 func StagedDiff() (string, error) {
 	return shell.Run("git", "diff", "--cached")
 }
 
-// Returns a summary of staged changes.
+// Returns a name-status summary of staged changes only.
 func StagedSummary() (string, error) {
+	return shell.Run("git", "diff", "--cached", "--name-status")
+}
+
+// Returns a short status of the whole working tree (staged, unstaged and
+// untracked).
+func WorkingTreeStatus() (string, error) {
 	// `git status --short` is familiar and readable
 	return shell.Run("git", "status", "--short")
 }
 
+// Returns true if anything is staged in the index.
+func HasStagedChanges() bool {
+	_, err := shell.Run("git", "diff", "--cached", "--quiet")
+	return err != nil
+}
+
+// Returns the full hash of HEAD.
+func HeadCommit() (string, error) {
+	out, err := shell.Run("git", "rev-parse", "HEAD")
+	return strings.TrimSpace(out), err
+}
+
+// Returns true if the given ref (branch, remote branch, tag) exists locally.
+func RefExists(ref string) bool {
+	_, err := shell.Run("git", "rev-parse", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// Returns how many commits from is ahead of and behind base.
+func AheadBehind(base, from string) (ahead, behind int, err error) {
+	out, err := shell.Run("git", "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", base, from))
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	behind, _ = strconv.Atoi(fields[0])
+	ahead, _ = strconv.Atoi(fields[1])
+	return ahead, behind, nil
+}
+
+// Returns the upstream of the current branch (e.g. "origin/feat/x"), or "".
+func UpstreamBranch() string {
+	out, err := shell.Run("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// Fetches the given refs (or everything when none are given) from origin.
+func FetchOrigin(refs ...string) error {
+	args := append([]string{"fetch", "origin"}, refs...)
+	_, err := mutate("git", args...)
+	return err
+}
+
+// Fast-forwards the current branch to the given ref; fails if that would
+// need a merge.
+func FastForward(ref string) error {
+	_, err := mutate("git", "merge", "--ff-only", ref)
+	return err
+}
+
+// Deletes a local branch even if git considers it unmerged (e.g. after a
+// squash merge). Callers must check that no work would be lost.
+func DeleteLocalBranch(name string) error {
+	_, err := mutate("git", "branch", "-D", name)
+	return err
+}
+
+// Deletes a branch on origin.
+func DeleteRemoteBranch(name string) error {
+	_, err := mutate("git", "push", "origin", "--delete", name)
+	return err
+}
+
+// Returns the number of commits reachable from head but not from base.
+func CountCommits(base, head string) (int, error) {
+	out, err := shell.Run("git", "rev-list", "--count", fmt.Sprintf("%s..%s", base, head))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// Returns the default branch of origin (from origin/HEAD), or "".
+func DefaultRemoteBranch() string {
+	out, err := shell.Run("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(out), "origin/")
+}
+
 func CheckoutBranch(name string) error {
-	_, err := shell.Run("git", "checkout", name)
+	_, err := mutate("git", "checkout", name)
 	return err
 }
 
@@ -119,7 +283,23 @@ func IsBranchPushed(branch string) bool {
 	return strings.TrimSpace(string(out)) != ""
 }
 
+// ForcePushBranch pushes rewritten history, refusing to overwrite commits
+// on origin that were not seen locally.
+func ForcePushBranch(branch string) error {
+	if planned("git", "push", "--force-with-lease", "-u", "origin", branch) {
+		return nil
+	}
+	cmd := exec.Command("git", "push", "--force-with-lease", "-u", "origin", branch)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
 func PushBranch(branch string) error {
+	if planned("git", "push", "-u", "origin", branch) {
+		return nil
+	}
 	cmd := exec.Command("git", "push", "-u", "origin", branch)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -0,0 +1,116 @@
+package gitflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// SigningError reports a commit that git could not sign.
+type SigningError struct {
+	Format string // "openpgp", "ssh" or "x509", from gpg.format
+	Key    string // user.signingkey, if set
+	Output string
+}
+
+func (e *SigningError) Error() string {
+	return fmt.Sprintf("commit signing failed (%s)", e.Format)
+}
+
+// isTrue reports whether a config value means true the way git reads it.
+func isTrue(v string) bool {
+	switch strings.ToLower(v) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// forceSigning reports whether `git config devgod.commit.sign true` asks
+// devgod to sign every commit even when commit.gpgsign is off.
+func forceSigning() bool {
+	return isTrue(configValue("commit.sign"))
+}
+
+// signingEnabled reports whether commits made now will be signed.
+func signingEnabled() bool {
+	return forceSigning() || isTrue(gitConfig("commit.gpgsign"))
+}
+
+// signArgs returns the flag that makes git sign a commit, rebase or merge
+// when devgod is configured to force signing. Otherwise git's own
+// commit.gpgsign applies as usual.
+func signArgs() []string {
+	if forceSigning() {
+		return []string{"-S"}
+	}
+	return nil
+}
+
+// Output that only gpg, gpgsm or ssh-keygen print when they cannot sign.
+// Git's own "failed to write commit object" is left out: it also follows
+// plain object-write failures such as a full disk.
+var signingFailures = []string{
+	"gpg failed to sign",
+	"gpg: ",
+	"gpgsm: ",
+	"error: load key",
+	"ssh-keygen",
+	"signing file",
+	"no private key",
+	"secret key not available",
+	"couldn't find key",
+	"couldn't load",
+}
+
+// signingError turns a failed commit into a *SigningError when signing was
+// on and git's output comes from the signer. Other errors pass through
+// unchanged.
+func signingError(out string, err error) error {
+	if err == nil || !signingEnabled() {
+		return err
+	}
+	lower := strings.ToLower(out)
+	for _, s := range signingFailures {
+		if strings.Contains(lower, s) {
+			format := gitConfig("gpg.format")
+			if format == "" {
+				format = "openpgp"
+			}
+			return &SigningError{Format: format, Key: gitConfig("user.signingkey"), Output: out}
+		}
+	}
+	return err
+}
+
+// printSigningFailure explains a signing failure and what usually fixes it.
+func printSigningFailure(signErr *SigningError) {
+	fmt.Println()
+	fmt.Println("🔏 " + ui.Red("Git could not sign the commit:"))
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+	for _, line := range strings.Split(strings.TrimRight(signErr.Output, "\n"), "\n") {
+		fmt.Println("  " + line)
+	}
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+
+	key := signErr.Key
+	if key == "" {
+		key = "(not set)"
+	}
+	fmt.Println("Signing format:", signErr.Format)
+	fmt.Println("Signing key:   ", key)
+
+	switch signErr.Format {
+	case "ssh":
+		fmt.Println(ui.Dim("Check that user.signingkey points at your SSH key (or its public key with the private key loaded in ssh-agent), e.g.:"))
+		fmt.Println(ui.Dim("  git config user.signingkey ~/.ssh/id_ed25519.pub && ssh-add ~/.ssh/id_ed25519"))
+	case "x509":
+		fmt.Println(ui.Dim("Check that gpgsm can see your certificate: gpgsm --list-secret-keys"))
+	default:
+		fmt.Println(ui.Dim("Check that the key exists and gpg can prompt for its passphrase, e.g.:"))
+		fmt.Println(ui.Dim("  gpg --list-secret-keys --keyid-format=long"))
+		fmt.Println(ui.Dim("  export GPG_TTY=$(tty)"))
+	}
+	fmt.Println(ui.Dim("Test it with: echo test | git commit-tree -S HEAD^{tree}"))
+}
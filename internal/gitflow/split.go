@@ -0,0 +1,768 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// splitCommit is one commit of a split plan. Files holds split units: a
+// whole path, or "path#N" for hunk N of a file that was cut into hunks.
+type splitCommit struct {
+	Message string
+	Files   []string
+}
+
+// stagedFile is the staged diff of one file, cut into hunks.
+type stagedFile struct {
+	Path string
+	// Header is the diff from "diff --git" up to the first hunk.
+	Header string
+	// Hunks each start with their "@@" line.
+	Hunks []string
+}
+
+// splittable reports whether the file's hunks may go to different commits.
+// Added, deleted, binary and mode-changed files always move whole.
+func (f stagedFile) splittable() bool {
+	if len(f.Hunks) < 2 {
+		return false
+	}
+	for _, marker := range []string{"\nnew file mode ", "\ndeleted file mode ", "\nold mode ", "\nBinary files ", "\nGIT binary patch"} {
+		if strings.Contains(f.Header, marker) {
+			return false
+		}
+	}
+	return true
+}
+
+// patch returns a patch holding just the given hunks (0-based), in order.
+func (f stagedFile) patch(hunks []int) string {
+	var sb strings.Builder
+	sb.WriteString(f.Header)
+	for i, h := range f.Hunks {
+		if slices.Contains(hunks, i) {
+			sb.WriteString(h)
+		}
+	}
+	return sb.String()
+}
+
+// stagedPaths lists files staged in the index file at index ("" for the
+// usual one), relative to the repo root. Renames are reported as a delete
+// plus an add so each path can be placed on its own.
+func stagedPaths(index string) ([]string, error) {
+	out, err := gitIn(index, "diff", "--cached", "--name-only", "--no-renames", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// splitDiffSections cuts a multi-file diff at each "diff --git" line.
+func splitDiffSections(diff string) []string {
+	var sections []string
+	var cur strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") && cur.Len() > 0 {
+			sections = append(sections, cur.String())
+			cur.Reset()
+		}
+		cur.WriteString(line)
+	}
+	if cur.Len() > 0 {
+		sections = append(sections, cur.String())
+	}
+	return sections
+}
+
+// parseFileDiff cuts one file's diff section into its header and hunks.
+func parseFileDiff(path, section string) stagedFile {
+	f := stagedFile{Path: path}
+	var header strings.Builder
+	for _, line := range strings.SplitAfter(section, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			f.Hunks = append(f.Hunks, line)
+		case len(f.Hunks) > 0:
+			f.Hunks[len(f.Hunks)-1] += line
+		default:
+			header.WriteString(line)
+		}
+	}
+	f.Header = header.String()
+	return f
+}
+
+// stagedFiles returns the staged diff of every staged file. If the diff
+// cannot be lined up with the file list, files come back without hunks
+// and are split whole.
+func stagedFiles() ([]stagedFile, error) {
+	paths, err := stagedPaths("")
+	if err != nil {
+		return nil, err
+	}
+	// Fixed prefixes so the hunks apply whatever diff.* config says
+	diff, err := shell.Run("git", "diff", "--cached", "--no-renames", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/")
+	if err != nil {
+		return nil, err
+	}
+
+	sections := splitDiffSections(diff)
+	files := make([]stagedFile, len(paths))
+	for i, p := range paths {
+		if len(sections) == len(paths) {
+			files[i] = parseFileDiff(p, sections[i])
+		} else {
+			files[i] = stagedFile{Path: p}
+		}
+	}
+	return files, nil
+}
+
+// hunkUnit names hunk i (0-based) of path as a split unit.
+func hunkUnit(path string, i int) string {
+	return fmt.Sprintf("%s#%d", path, i+1)
+}
+
+// unitPath returns the file a split unit belongs to.
+func unitPath(u string) string {
+	if i := strings.LastIndex(u, "#"); i > 0 {
+		if _, err := strconv.Atoi(u[i+1:]); err == nil {
+			return u[:i]
+		}
+	}
+	return u
+}
+
+// resolveUnit returns the file and hunk (0-based) of a split unit, with
+// hunk -1 for a whole file.
+func resolveUnit(u string, files map[string]stagedFile) (string, int) {
+	if _, ok := files[u]; ok {
+		return u, -1
+	}
+	p := unitPath(u)
+	n, _ := strconv.Atoi(u[len(p)+1:])
+	return p, n - 1
+}
+
+// splitUnits lists what a split distributes: each hunk of a splittable
+// file, and every other file whole.
+func splitUnits(files []stagedFile) []string {
+	var units []string
+	for _, f := range files {
+		if !f.splittable() {
+			units = append(units, f.Path)
+			continue
+		}
+		for i := range f.Hunks {
+			units = append(units, hunkUnit(f.Path, i))
+		}
+	}
+	return units
+}
+
+// unitLabels describes each split unit for the preview, e.g.
+// "a.go (hunk 2 of 3: @@ -40,7 +41,9 @@ func parse)".
+func unitLabels(files []stagedFile) map[string]string {
+	labels := map[string]string{}
+	for _, f := range files {
+		if !f.splittable() {
+			labels[f.Path] = f.Path
+			continue
+		}
+		for i, h := range f.Hunks {
+			first := strings.TrimSpace(strings.SplitN(h, "\n", 2)[0])
+			labels[hunkUnit(f.Path, i)] = fmt.Sprintf("%s (hunk %d of %d: %s)", f.Path, i+1, len(f.Hunks), first)
+		}
+	}
+	return labels
+}
+
+// describeHunks lists the hunks of splittable files for the model: the
+// unit name, the hunk header and its first changed lines.
+func describeHunks(files []stagedFile) string {
+	var sb strings.Builder
+	for _, f := range files {
+		if !f.splittable() {
+			continue
+		}
+		for i, h := range f.Hunks {
+			lines := strings.Split(strings.TrimRight(h, "\n"), "\n")
+			fmt.Fprintf(&sb, "%s %s\n", hunkUnit(f.Path, i), lines[0])
+			shown := 0
+			for _, l := range lines[1:] {
+				if shown < 3 && (strings.HasPrefix(l, "+") || strings.HasPrefix(l, "-")) {
+					fmt.Fprintf(&sb, "    %s\n", l)
+					shown++
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// groupKey is the directory used to cluster a file: its first two path
+// segments, so "internal/gitflow/a.go" and "internal/gitflow/b.go" end up
+// together while "cmd/x.go" does not.
+func groupKey(p string) string {
+	dir := path.Dir(p)
+	parts := strings.Split(dir, "/")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, "/")
+}
+
+// groupByDirectory clusters files by groupKey, keeping the order in which
+// directories first appear.
+func groupByDirectory(files []string) [][]string {
+	var keys []string
+	groups := map[string][]string{}
+	for _, f := range files {
+		k := groupKey(f)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], f)
+	}
+
+	out := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, groups[k])
+	}
+	return out
+}
+
+// reconcileSplit makes the proposed commits cover the split units exactly
+// once: unknown entries and duplicates are dropped, a plain path for a file
+// cut into hunks takes all of its hunks still free, and units the model
+// forgot join a commit with another hunk of the same file, else one touching
+// the same directory, else a new commit at the end.
+func reconcileSplit(proposed []ai.CommitGroup, units []string) []splitCommit {
+	known := map[string]bool{}
+	for _, u := range units {
+		known[u] = true
+	}
+
+	used := map[string]bool{}
+	take := func(c *splitCommit, u string) {
+		if known[u] && !used[u] {
+			used[u] = true
+			c.Files = append(c.Files, u)
+		}
+	}
+
+	var commits []splitCommit
+	for _, g := range proposed {
+		c := splitCommit{Message: strings.TrimSpace(g.Message)}
+		for _, f := range g.Files {
+			f = strings.TrimPrefix(strings.TrimSpace(f), "./")
+			if known[f] {
+				take(&c, f)
+				continue
+			}
+			for _, u := range units {
+				if u != f && unitPath(u) == f {
+					take(&c, u)
+				}
+			}
+		}
+		if len(c.Files) > 0 {
+			commits = append(commits, c)
+		}
+	}
+
+	var leftover []string
+	for _, u := range units {
+		if used[u] {
+			continue
+		}
+		target := slices.IndexFunc(commits, func(c splitCommit) bool {
+			return slices.ContainsFunc(c.Files, func(o string) bool { return unitPath(o) == unitPath(u) })
+		})
+		if target < 0 {
+			target = slices.IndexFunc(commits, func(c splitCommit) bool {
+				return slices.ContainsFunc(c.Files, func(o string) bool { return groupKey(o) == groupKey(u) })
+			})
+		}
+		if target < 0 {
+			leftover = append(leftover, u)
+			continue
+		}
+		commits[target].Files = append(commits[target].Files, u)
+	}
+	if len(leftover) > 0 {
+		commits = append(commits, splitCommit{Files: leftover})
+	}
+	return commits
+}
+
+// unitsDiff renders the staged diff of just the given units.
+func unitsDiff(units []string, files map[string]stagedFile) string {
+	var order []string
+	hunks := map[string][]int{}
+	whole := map[string]bool{}
+	for _, u := range units {
+		p, h := resolveUnit(u, files)
+		if _, ok := hunks[p]; !ok && !whole[p] {
+			order = append(order, p)
+		}
+		if h < 0 {
+			whole[p] = true
+			continue
+		}
+		hunks[p] = append(hunks[p], h)
+	}
+
+	var sb strings.Builder
+	for _, p := range order {
+		f := files[p]
+		if whole[p] {
+			sb.WriteString(f.Header + strings.Join(f.Hunks, ""))
+		} else {
+			sb.WriteString(f.patch(hunks[p]))
+		}
+	}
+	return sb.String()
+}
+
+// unitPaths returns the distinct files of the given units, in order.
+func unitPaths(units []string, files map[string]stagedFile) []string {
+	var paths []string
+	for _, u := range units {
+		if p, _ := resolveUnit(u, files); !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// fillSplitMessages generates a message for every commit that has none,
+// from the diff of just its files and hunks.
+func fillSplitMessages(commits []splitCommit, files map[string]stagedFile, intent string) error {
+	for i := range commits {
+		if commits[i].Message != "" {
+			continue
+		}
+		summaryArgs := append([]string{"diff", "--cached", "--name-status", "--no-renames", "--"}, rootPaths(unitPaths(commits[i].Files, files))...)
+		summary, _ := shell.Run("git", summaryArgs...)
+
+		msg, err := ai.GenerateCommitMessage(intent, summary, unitsDiff(commits[i].Files, files))
+		if err != nil {
+			return err
+		}
+		commits[i].Message = msg
+	}
+	return nil
+}
+
+// rootPaths turns repo-root relative paths into pathspecs that work from
+// any subdirectory.
+func rootPaths(files []string) []string {
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = ":(top)" + f
+	}
+	return out
+}
+
+// printSplitPlan shows the proposed sequence of commits.
+func printSplitPlan(branch string, commits []splitCommit, labels map[string]string) {
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("🚀 DEVGOD SPLIT PREVIEW"))
+	fmt.Println("🌿 " + ui.BranchLabelStyle.Render("Branch:"))
+	fmt.Println("   " + ui.ValueStyle.Render(branch))
+
+	for i, c := range commits {
+		fmt.Println()
+		fmt.Println("✍️  " + ui.CommitLabelStyle.Render(fmt.Sprintf("Commit %d of %d:", i+1, len(commits))))
+		fmt.Println("   " + ui.ValueStyle.Render(c.Message))
+		for _, f := range c.Files {
+			fmt.Println("     " + ui.Dim(labels[f]))
+		}
+	}
+	fmt.Println()
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+}
+
+const (
+	splitCreate  = "Create these commits"
+	splitMove    = "Move a file or hunk to another commit"
+	splitMessage = "Edit a commit message"
+	splitOne     = "Make it a single commit instead"
+	splitCancel  = "Cancel"
+)
+
+// adjustSplit lets the user review and tweak the plan. It returns the final
+// commits, or nil when the user cancelled. single reports that the user
+// asked for one commit after all.
+func adjustSplit(branch string, commits []splitCommit, labels map[string]string) (final []splitCommit, single bool, err error) {
+	for {
+		printSplitPlan(branch, commits, labels)
+		if ui.AssumeYes() {
+			return commits, false, nil
+		}
+
+		actions := []string{splitCreate, splitMove, splitMessage, splitOne, splitCancel}
+		for i, a := range actions {
+			fmt.Printf("  %2d) %s\n", i+1, a)
+		}
+		action, err := ui.SelectOne(actions, ui.Cyan("What next?"))
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch action {
+		case splitCreate:
+			return commits, false, nil
+		case splitOne:
+			return nil, true, nil
+		case splitCancel:
+			return nil, false, nil
+		case splitMove:
+			commits, err = moveSplitUnit(commits, labels)
+		case splitMessage:
+			err = editSplitMessage(commits)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// askCommitNumber reads a 1-based commit number in [1, max].
+func askCommitNumber(prompt string, max int) (int, error) {
+	for {
+		answer, err := ui.Input(prompt)
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= 1 && n <= max {
+			return n - 1, nil
+		}
+		fmt.Printf("Please enter a number between 1 and %d.\n", max)
+	}
+}
+
+// moveSplitUnit moves one file or hunk to another (possibly new) commit.
+func moveSplitUnit(commits []splitCommit, labels map[string]string) ([]splitCommit, error) {
+	var files []string
+	from := map[string]int{}
+	for i, c := range commits {
+		for _, f := range c.Files {
+			files = append(files, f)
+			from[f] = i
+		}
+	}
+	for i, f := range files {
+		fmt.Printf("  %2d) %s %s\n", i+1, labels[f], ui.Dim(fmt.Sprintf("(commit %d)", from[f]+1)))
+	}
+	file, err := ui.SelectOne(files, ui.Cyan("Which file or hunk?"))
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := askCommitNumber(ui.Cyan(fmt.Sprintf("Move it to which commit? (1-%d, or %d for a new commit)", len(commits), len(commits)+1)), len(commits)+1)
+	if err != nil {
+		return nil, err
+	}
+	if to == len(commits) {
+		commits = append(commits, splitCommit{})
+	}
+
+	src := from[file]
+	commits[src].Files = slices.DeleteFunc(commits[src].Files, func(f string) bool { return f == file })
+	commits[to].Files = append(commits[to].Files, file)
+	if commits[to].Message == "" {
+		commits[to].Message = commits[src].Message
+	}
+
+	// Drop commits that were emptied by the move
+	return slices.DeleteFunc(commits, func(c splitCommit) bool { return len(c.Files) == 0 }), nil
+}
+
+// editSplitMessage replaces the message of one commit.
+func editSplitMessage(commits []splitCommit) error {
+	n, err := askCommitNumber(ui.Cyan(fmt.Sprintf("Edit which commit's message? (1-%d)", len(commits))), len(commits))
+	if err != nil {
+		return err
+	}
+	msg, err := ui.Input(ui.Cyan("New message:"))
+	if err != nil {
+		return err
+	}
+	if msg != "" {
+		commits[n].Message = msg
+	}
+	return nil
+}
+
+// gitWithIndex runs git against an alternate index file.
+func gitWithIndex(index string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+index)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("command failed: git %s\n%w\noutput:\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out), nil
+}
+
+// gitIn runs git against the index file at index, or the usual index when
+// index is "".
+func gitIn(index string, args ...string) (string, error) {
+	if index == "" {
+		return shell.Run("git", args...)
+	}
+	return gitWithIndex(index, args...)
+}
+
+// takeSnapshotEntry sets path in the index file at index to its entry in
+// the snapshot tree, removing it when the snapshot deleted it.
+func takeSnapshotEntry(root, index, tree, path string) error {
+	entry, err := shell.Run("git", "-C", root, "ls-tree", tree, "--", path)
+	if err != nil {
+		return err
+	}
+	if entry = strings.TrimSpace(entry); entry == "" {
+		_, err := gitWithIndex(index, "-C", root, "update-index", "--force-remove", "--", path)
+		return err
+	}
+	// "<mode> <type> <hash>\t<path>"
+	meta := strings.Fields(strings.SplitN(entry, "\t", 2)[0])
+	if len(meta) < 3 {
+		return fmt.Errorf("unexpected ls-tree output: %q", entry)
+	}
+	_, err = gitWithIndex(index, "-C", root, "update-index", "--add", "--cacheinfo", meta[0]+","+meta[2]+","+path)
+	return err
+}
+
+// applyHunks applies the given hunks of f to the index file at index.
+func applyHunks(root, index string, f stagedFile, hunks []int) error {
+	tmp, err := os.CreateTemp("", "devgod-split-patch-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(f.patch(hunks)); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	if _, err := gitWithIndex(index, "-C", root, "apply", "--cached", "--whitespace=nowarn", tmp.Name()); err != nil {
+		return fmt.Errorf("hunks of %s no longer apply (did a hook change the file?): %w", f.Path, err)
+	}
+	return nil
+}
+
+// commitSplit creates the commits in order. Each commit is built in a
+// temporary index that starts from HEAD: whole files take their entry from
+// the staged snapshot, hunks are applied with `git apply --cached`. Once a
+// file is fully committed, its real index entry is reset to HEAD, which
+// matches the snapshot unless a hook re-staged it; files not committed yet
+// stay staged as they were. Fewer hashes than commits with a nil error
+// means the user cancelled partway.
+func commitSplit(commits []splitCommit, files map[string]stagedFile) ([]string, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot of everything staged
+	tree, err := shell.Run("git", "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the index: %w", err)
+	}
+	tree = strings.TrimSpace(tree)
+
+	tmp, err := os.CreateTemp("", "devgod-split-index-*")
+	if err != nil {
+		return nil, err
+	}
+	index := tmp.Name()
+	tmp.Close()
+	defer os.Remove(index)
+
+	// Hunks of each file committed so far
+	done := map[string]int{}
+
+	var hashes []string
+	for i, c := range commits {
+		if dryRun {
+			var parts []string
+			for _, u := range c.Files {
+				if p, h := resolveUnit(u, files); h >= 0 {
+					parts = append(parts, fmt.Sprintf("%s (hunk %d)", p, h+1))
+				} else {
+					parts = append(parts, p)
+				}
+			}
+			plannedAction(fmt.Sprintf("commit %d of %d takes %s", i+1, len(commits), strings.Join(parts, ", ")))
+			announce("git", append([]string{"commit", "-m", c.Message}, signArgs()...)...)
+			continue
+		}
+
+		// read-tree refuses an empty file, so start from nothing
+		os.Remove(index)
+		if _, err := gitWithIndex(index, "-C", root, "read-tree", "HEAD"); err != nil {
+			if _, err := gitWithIndex(index, "-C", root, "read-tree", "--empty"); err != nil {
+				return hashes, err
+			}
+		}
+
+		hunks := map[string][]int{}
+		var complete []string
+		for _, p := range unitPaths(c.Files, files) {
+			hunks[p] = nil
+		}
+		for _, u := range c.Files {
+			if p, h := resolveUnit(u, files); h >= 0 {
+				hunks[p] = append(hunks[p], h)
+			}
+		}
+		for _, p := range unitPaths(c.Files, files) {
+			f := files[p]
+			// The last hunks of a file take the snapshot entry, exactly
+			if len(hunks[p]) == 0 || done[p]+len(hunks[p]) == len(f.Hunks) {
+				if err := takeSnapshotEntry(root, index, tree, p); err != nil {
+					return hashes, err
+				}
+				complete = append(complete, p)
+				continue
+			}
+			if err := applyHunks(root, index, f, hunks[p]); err != nil {
+				return hashes, fmt.Errorf("commit %d of %d: %w", i+1, len(commits), err)
+			}
+		}
+
+		committed, err := commitWithRetryIn(index, c.Message)
+		if err != nil {
+			return hashes, fmt.Errorf("commit %d of %d failed: %w", i+1, len(commits), err)
+		}
+		if !committed {
+			return hashes, nil
+		}
+		hash, err := HeadCommit()
+		if err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, hash)
+		for p, h := range hunks {
+			done[p] += len(h)
+		}
+
+		// Bring the real index in line for what is now fully committed,
+		// including anything a hook re-staged into the temporary index
+		if len(complete) > 0 {
+			args := append([]string{"-C", root, "reset", "-q", "HEAD", "--"}, rootPaths(complete)...)
+			if _, err := shell.Run("git", args...); err != nil {
+				return hashes, err
+			}
+		}
+		fmt.Println(ui.Green(fmt.Sprintf("✔️ Commit %d of %d:", i+1, len(commits))), strings.SplitN(c.Message, "\n", 2)[0])
+	}
+
+	_, _ = shell.Run("git", "-C", root, "update-index", "-q", "--refresh")
+	return hashes, nil
+}
+
+// finishSplit proposes and creates several commits from the staged changes.
+// It returns single=true when there is nothing to split or the user asked
+// for one commit, so the caller can fall back to the normal flow.
+func finishSplit(task *ActiveTask, summary, contextForAI string) (single bool, err error) {
+	staged, err := stagedFiles()
+	if err != nil {
+		return false, err
+	}
+	units := splitUnits(staged)
+	if len(units) < 2 {
+		fmt.Println(ui.Dim("Only one change is staged; nothing to split."))
+		return true, nil
+	}
+
+	files := map[string]stagedFile{}
+	var paths []string
+	for _, f := range staged {
+		files[f.Path] = f
+		paths = append(paths, f.Path)
+	}
+	hint := groupByDirectory(paths)
+
+	stop := ui.StartSpinner("Splitting the changes into commits...")
+	proposed, err := ai.GenerateCommitSplit(task.Intent, summary, describeHunks(staged), contextForAI, hint)
+	stop()
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not get a split from AI; grouping by directory instead."))
+		proposed = nil
+		for _, g := range hint {
+			proposed = append(proposed, ai.CommitGroup{Files: g})
+		}
+	}
+
+	commits := reconcileSplit(proposed, units)
+
+	stop = ui.StartSpinner("Letting the commit gods cook...")
+	err = fillSplitMessages(commits, files, task.Intent)
+	stop()
+	if err != nil {
+		fmt.Println(ui.Red("❌ Failed to generate commit messages with AI."))
+		return false, err
+	}
+
+	commits, single, err = adjustSplit(task.Branch, commits, unitLabels(staged))
+	if err != nil || single {
+		return single, err
+	}
+	if commits == nil {
+		fmt.Println("❌ Commit cancelled.")
+		return false, nil
+	}
+
+	for i := range commits {
+		if commits[i].Message == "" {
+			commits[i].Message = task.Intent
+		}
+		commits[i].Message = withTaskTrailers(commits[i].Message, task)
+	}
+
+	hashes, err := commitSplit(commits, files)
+	if dryRun {
+		return false, err
+	}
+
+	// Record whatever was committed, even if a later commit failed
+	if len(hashes) > 0 {
+		stateErr := UpdateTask(task.Branch, func(t *ActiveTask) {
+			for i, h := range hashes {
+				t.recordCommit(h, commits[i].Message)
+			}
+		})
+		if stateErr != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), stateErr)
+		}
+	}
+	if err != nil || len(hashes) < len(commits) {
+		fmt.Println(ui.Yellow("⚠️ The remaining changes are still staged."))
+		return false, err
+	}
+
+	fmt.Printf("✅ Created %d commits.\n", len(hashes))
+	return false, nil
+}
@@ -0,0 +1,178 @@
+package gitflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+)
+
+func TestReconcileSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		proposed []ai.CommitGroup
+		units    []string
+		want     []splitCommit
+	}{
+		{
+			name: "exact cover",
+			proposed: []ai.CommitGroup{
+				{Message: "refactor: a", Files: []string{"lib/a.go"}},
+				{Message: "docs: b", Files: []string{"docs/b.md"}},
+			},
+			units: []string{"lib/a.go", "docs/b.md"},
+			want: []splitCommit{
+				{Message: "refactor: a", Files: []string{"lib/a.go"}},
+				{Message: "docs: b", Files: []string{"docs/b.md"}},
+			},
+		},
+		{
+			name: "invented and duplicate paths dropped",
+			proposed: []ai.CommitGroup{
+				{Message: "feat: a", Files: []string{"lib/a.go", "lib/ghost.go", "./lib/a.go"}},
+				{Message: "feat: ghost", Files: []string{"nowhere.go"}},
+				{Message: "docs: b", Files: []string{"./docs/b.md", "lib/a.go"}},
+			},
+			units: []string{"lib/a.go", "docs/b.md"},
+			want: []splitCommit{
+				{Message: "feat: a", Files: []string{"lib/a.go"}},
+				{Message: "docs: b", Files: []string{"docs/b.md"}},
+			},
+		},
+		{
+			name: "dropped file joins its directory",
+			proposed: []ai.CommitGroup{
+				{Message: "feat: a", Files: []string{"lib/a.go"}},
+			},
+			units: []string{"lib/a.go", "lib/b.go"},
+			want: []splitCommit{
+				{Message: "feat: a", Files: []string{"lib/a.go", "lib/b.go"}},
+			},
+		},
+		{
+			name: "dropped file without a match gets its own commit",
+			proposed: []ai.CommitGroup{
+				{Message: "feat: a", Files: []string{"lib/a.go"}},
+			},
+			units: []string{"lib/a.go", "README.md", "cmd/x.go"},
+			want: []splitCommit{
+				{Message: "feat: a", Files: []string{"lib/a.go"}},
+				{Files: []string{"README.md", "cmd/x.go"}},
+			},
+		},
+		{
+			name: "whole path takes the hunks still free",
+			proposed: []ai.CommitGroup{
+				{Message: "fix: a", Files: []string{"lib/a.go#2"}},
+				{Message: "feat: a", Files: []string{"lib/a.go"}},
+			},
+			units: []string{"lib/a.go#1", "lib/a.go#2", "lib/a.go#3"},
+			want: []splitCommit{
+				{Message: "fix: a", Files: []string{"lib/a.go#2"}},
+				{Message: "feat: a", Files: []string{"lib/a.go#1", "lib/a.go#3"}},
+			},
+		},
+		{
+			name: "dropped hunk joins another hunk of its file",
+			proposed: []ai.CommitGroup{
+				{Message: "docs: b", Files: []string{"lib/b.md"}},
+				{Message: "fix: a", Files: []string{"lib/a.go#1"}},
+			},
+			units: []string{"lib/a.go#1", "lib/a.go#2", "lib/b.md"},
+			want: []splitCommit{
+				{Message: "docs: b", Files: []string{"lib/b.md"}},
+				{Message: "fix: a", Files: []string{"lib/a.go#1", "lib/a.go#2"}},
+			},
+		},
+		{
+			name:     "no proposal",
+			proposed: nil,
+			units:    []string{"a.go", "b.go"},
+			want:     []splitCommit{{Files: []string{"a.go", "b.go"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reconcileSplit(tt.proposed, tt.units)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reconcileSplit = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+const twoHunkDiff = `diff --git a/lib/a.txt b/lib/a.txt
+index 1111111..2222222 100644
+--- a/lib/a.txt
++++ b/lib/a.txt
+@@ -1,3 +1,3 @@
+ 1
+-2
++two
+ 3
+@@ -37,3 +37,3 @@
+ 37
+-38
++thirty-eight
+ 39
+`
+
+const newFileDiff = `diff --git a/docs/b.md b/docs/b.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/b.md
+@@ -0,0 +1 @@
++doc
+`
+
+func TestParseStagedDiff(t *testing.T) {
+	sections := splitDiffSections(twoHunkDiff + newFileDiff)
+	if len(sections) != 2 || sections[0] != twoHunkDiff || sections[1] != newFileDiff {
+		t.Fatalf("splitDiffSections = %q", sections)
+	}
+
+	a := parseFileDiff("lib/a.txt", sections[0])
+	if len(a.Hunks) != 2 || !a.splittable() {
+		t.Fatalf("lib/a.txt: %d hunks, splittable %v; want 2 and true", len(a.Hunks), a.splittable())
+	}
+	if a.Header+a.Hunks[0]+a.Hunks[1] != twoHunkDiff {
+		t.Errorf("header and hunks do not add up to the section")
+	}
+	wantPatch := a.Header + a.Hunks[1]
+	if got := a.patch([]int{1}); got != wantPatch {
+		t.Errorf("patch([1]) = %q, want %q", got, wantPatch)
+	}
+
+	b := parseFileDiff("docs/b.md", sections[1])
+	if b.splittable() {
+		t.Errorf("a new file must not be splittable")
+	}
+
+	units := splitUnits([]stagedFile{a, b})
+	wantUnits := []string{"lib/a.txt#1", "lib/a.txt#2", "docs/b.md"}
+	if !reflect.DeepEqual(units, wantUnits) {
+		t.Errorf("splitUnits = %q, want %q", units, wantUnits)
+	}
+}
+
+func TestResolveUnit(t *testing.T) {
+	files := map[string]stagedFile{"a.go": {Path: "a.go"}, "notes#1": {Path: "notes#1"}}
+	tests := []struct {
+		unit     string
+		wantPath string
+		wantHunk int
+	}{
+		{unit: "a.go", wantPath: "a.go", wantHunk: -1},
+		{unit: "a.go#3", wantPath: "a.go", wantHunk: 2},
+		{unit: "notes#1", wantPath: "notes#1", wantHunk: -1}, // a file named like a hunk
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			p, h := resolveUnit(tt.unit, files)
+			if p != tt.wantPath || h != tt.wantHunk {
+				t.Errorf("resolveUnit(%q) = %q, %d; want %q, %d", tt.unit, p, h, tt.wantPath, tt.wantHunk)
+			}
+		})
+	}
+}
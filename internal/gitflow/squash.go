@@ -0,0 +1,243 @@
+package gitflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/ai"
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// squashBackupRef is where the branch tip is saved before a squash.
+func squashBackupRef(branch string) string {
+	return "refs/devgod/backup/" + branch
+}
+
+// forkPoint returns the commit the task branch forked from base, preferring
+// the reflog-aware --fork-point and falling back to the plain merge base.
+func forkPoint(base string) (string, error) {
+	if out, err := shell.Run("git", "merge-base", "--fork-point", base, "HEAD"); err == nil && strings.TrimSpace(out) != "" {
+		return strings.TrimSpace(out), nil
+	}
+	out, err := shell.Run("git", "merge-base", base, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to find where the branch forked from %s: %w", base, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// currentTask returns the task for the checked-out branch, or for the
+// branch being rebased while HEAD is detached mid-rebase.
+func currentTask() (*ActiveTask, error) {
+	state, err := LoadState()
+	if err != nil {
+		return nil, err
+	}
+	branch, err := CurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if branch == "HEAD" {
+		if b := rebasingBranch(); b != "" {
+			branch = b
+		}
+	}
+	task := state.TaskForBranch(branch)
+	if task == nil {
+		return nil, fmt.Errorf("no task found for branch %s. Run `devgod git \"your intent\"` or `devgod tasks switch` first", branch)
+	}
+	return task, nil
+}
+
+// Squash rewrites the task branch into a single commit with an AI-written
+// message. The previous tip is kept under refs/devgod/backup/<branch> so
+// the squash can be undone with SquashUndo.
+func Squash(baseFlag string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	task, err := currentTask()
+	if err != nil {
+		return err
+	}
+
+	if HasTrackedChanges() {
+		return fmt.Errorf("you have uncommitted changes; commit or stash them before squashing")
+	}
+
+	baseBranch := strings.TrimSpace(baseFlag)
+	if baseBranch == "" {
+		baseBranch = task.BaseBranch
+	}
+	base := taskBaseRef(baseBranch)
+	if base == "" {
+		return fmt.Errorf("don't know which branch %s started from; pass --base", task.Branch)
+	}
+
+	fork, err := forkPoint(base)
+	if err != nil {
+		return err
+	}
+
+	out, err := shell.Run("git", "log", "--reverse", "--format=%s", fork+"..HEAD")
+	if err != nil {
+		return err
+	}
+	var subjects []string
+	for _, s := range strings.Split(strings.TrimSpace(out), "\n") {
+		if s != "" {
+			subjects = append(subjects, s)
+		}
+	}
+	if len(subjects) < 2 {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ %s has %d commit(s) since %s; nothing to squash.", task.Branch, len(subjects), base)))
+		return nil
+	}
+
+	summary, _ := shell.Run("git", "diff", "--name-status", fork, "HEAD")
+	diff, err := shell.Run("git", "diff", fork, "HEAD")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		fmt.Println(ui.Yellow("⚠️ The commits cancel each other out; there is nothing left to squash into."))
+		return nil
+	}
+
+	contextForAI := commitContextForAI(diff, summary)
+
+	stop := ui.StartSpinner("Letting the commit gods cook...")
+	subject, err := ai.GenerateSquashMessage(task.Intent, summary, contextForAI, subjects)
+	stop()
+	if err != nil {
+		fmt.Println(ui.Red("❌ Failed to generate commit message with AI."))
+		return err
+	}
+
+	// Keep the original steps in the body, minus fixup noise
+	var body []string
+	for _, s := range subjects {
+		if !isAutosquashSubject(s) {
+			body = append(body, "- "+s)
+		}
+	}
+	commitMsg := subject
+	if len(body) > 0 {
+		commitMsg += "\n\n" + strings.Join(body, "\n")
+	}
+	commitMsg = withTaskTrailers(commitMsg, task)
+
+	ui.PrintCommitPlan(ui.CommitPlan{
+		Branch:        task.Branch,
+		Intent:        task.Intent,
+		StagedSummary: summary,
+		CommitMessage: commitMsg,
+	})
+	fmt.Println(ui.Dim(fmt.Sprintf("Squashing %d commits since %s (%s).", len(subjects), base, shortHash(fork))))
+
+	head, err := HeadCommit()
+	if err != nil {
+		return err
+	}
+	if isPushed(head, task.Branch) {
+		fmt.Println(ui.Yellow("⚠️ This branch is already pushed; you will need to force-push afterwards."))
+	}
+
+	if !ui.Confirm(fmt.Sprintf("Squash %d commits into this one?", len(subjects))) {
+		fmt.Println("❌ Squash cancelled.")
+		return nil
+	}
+
+	backup := squashBackupRef(task.Branch)
+	if _, err := mutate("git", "update-ref", "-m", "devgod squash backup", backup, head); err != nil {
+		return fmt.Errorf("failed to save backup ref: %w", err)
+	}
+
+	if _, err := mutate("git", "reset", "--soft", fork); err != nil {
+		return fmt.Errorf("failed to rewind branch: %w", err)
+	}
+	committed, err := commitWithRetry(commitMsg)
+	if dryRun {
+		return err
+	}
+	if err != nil || !committed {
+		// Put the branch back exactly as it was
+		_, _ = shell.Run("git", "reset", "--soft", head)
+		_, _ = shell.Run("git", "update-ref", "-d", backup)
+		if err != nil {
+			return fmt.Errorf("commit failed, branch restored: %w", err)
+		}
+		fmt.Println("Branch restored to its original commits.")
+		return nil
+	}
+
+	fmt.Println("✅ Branch squashed:")
+	fmt.Println(commitMsg)
+	fmt.Println(ui.Dim("Undo with: devgod squash --undo"))
+
+	if hash, err := HeadCommit(); err == nil {
+		err := UpdateTask(task.Branch, func(t *ActiveTask) {
+			t.SquashedCommits = t.Commits
+			t.Commits = nil
+			t.recordCommit(hash, commitMsg)
+		})
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+		}
+	}
+	return nil
+}
+
+// SquashUndo moves the task branch back to the tip saved by the last squash.
+func SquashUndo() error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	task, err := currentTask()
+	if err != nil {
+		return err
+	}
+
+	backup := squashBackupRef(task.Branch)
+	if !RefExists(backup) {
+		return fmt.Errorf("no squash backup found for %s", task.Branch)
+	}
+	if HasTrackedChanges() {
+		return fmt.Errorf("you have uncommitted changes; commit or stash them before undoing the squash")
+	}
+
+	out, err := shell.Run("git", "log", "--oneline", "-n", "5", backup)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ui.Green("Restoring the branch to:"))
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fmt.Println("   " + line)
+	}
+
+	if !ui.Confirm(fmt.Sprintf("Reset %s to the pre-squash commits?", task.Branch)) {
+		fmt.Println("❌ Undo cancelled.")
+		return nil
+	}
+
+	if _, err := mutate("git", "reset", "--keep", backup); err != nil {
+		return fmt.Errorf("failed to restore branch: %w", err)
+	}
+	if _, err := mutate("git", "update-ref", "-d", backup); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not delete the backup ref:"), err)
+	}
+
+	fmt.Println(ui.Green("✔️ Squash undone."))
+	err = UpdateTask(task.Branch, func(t *ActiveTask) {
+		t.Commits = t.SquashedCommits
+		t.SquashedCommits = nil
+		t.touch()
+	})
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package gitflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// ChangedFile is one path with uncommitted changes in the working tree.
+type ChangedFile struct {
+	Status  string // two-letter porcelain status, e.g. " M", "??", " D"
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// Untracked reports whether git does not know the file yet.
+func (f ChangedFile) Untracked() bool { return f.Status == "??" }
+
+// Label renders the file for the selection list, e.g. "M  src/app.go (+12 -3)".
+func (f ChangedFile) Label() string {
+	code := strings.TrimSpace(f.Status)
+	if f.Untracked() {
+		code = "A"
+	}
+	counts := fmt.Sprintf("(+%d -%d)", f.Added, f.Deleted)
+	if f.Binary {
+		counts = "(binary)"
+	}
+	return fmt.Sprintf("%-2s %s %s", code, f.Path, counts)
+}
+
+// changedFiles lists working tree changes that are not staged yet, with
+// line counts.
+func changedFiles() ([]ChangedFile, error) {
+	out, err := shell.Run("git", "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []ChangedFile
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		if len(e) < 4 {
+			continue
+		}
+		status, path := e[:2], e[3:]
+		// Renames/copies are followed by the original path
+		if status[0] == 'R' || status[0] == 'C' {
+			i++
+		}
+		// Only paths with something left to stage
+		if status[1] == ' ' {
+			continue
+		}
+		files = append(files, ChangedFile{Status: status, Path: path})
+	}
+
+	root, err := RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	// Line counts for tracked files in one call
+	counts := map[string][2]int{}
+	binary := map[string]bool{}
+	if numstat, err := shell.Run("git", "-C", root, "diff", "--numstat"); err == nil {
+		for _, line := range strings.Split(numstat, "\n") {
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			if parts[0] == "-" {
+				binary[parts[2]] = true
+			}
+			counts[parts[2]] = [2]int{parseNumstatField(parts[0]), parseNumstatField(parts[1])}
+		}
+	}
+
+	for i := range files {
+		f := &files[i]
+		if f.Untracked() {
+			f.Added, f.Binary = countFileLines(filepath.Join(root, f.Path))
+			continue
+		}
+		c := counts[f.Path]
+		f.Added, f.Deleted, f.Binary = c[0], c[1], binary[f.Path]
+	}
+	return files, nil
+}
+
+// countFileLines counts lines in a new file and reports binary content.
+func countFileLines(path string) (int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	if bytes.IndexByte(data, 0) != -1 {
+		return 0, true
+	}
+	n := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		n++
+	}
+	return n, false
+}
+
+// stageInteractively runs `git add -p` on the given paths attached to the
+// terminal so the user can pick individual hunks.
+func stageInteractively(paths []string) error {
+	args := append([]string{"add", "-p", "--"}, paths...)
+	announce("git", args...)
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// prepareIndex makes sure the index holds exactly what the user wants to
+// commit. An index that already has staged changes is respected as-is;
+// otherwise the user picks files (and optionally hunks) to stage.
+func prepareIndex() error {
+	if HasStagedChanges() {
+		if HasUnstagedChanges() {
+			fmt.Println(ui.Dim("Using the files you already staged; other changes are left out of this commit."))
+		}
+		return nil
+	}
+
+	files, err := changedFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	labels := make([]string, len(files))
+	byLabel := make(map[string]ChangedFile, len(files))
+	fmt.Println()
+	fmt.Println(ui.Green("Changed files:"))
+	for i, f := range files {
+		labels[i] = f.Label()
+		byLabel[labels[i]] = f
+		fmt.Printf("  %2d) %s\n", i+1, ui.ColorizeStatusLine(labels[i]))
+	}
+	fmt.Println()
+
+	selected, err := ui.SelectMultiple(labels, ui.Cyan("Select files to commit (comma-separated, 'a' for all, blank for none):"))
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	var whole, hunkable []string
+	for _, label := range selected {
+		f := byLabel[label]
+		// Only modified tracked text files can be split into hunks
+		if !f.Untracked() && !f.Binary && f.Status[1] == 'M' {
+			hunkable = append(hunkable, f.Path)
+		} else {
+			whole = append(whole, f.Path)
+		}
+	}
+
+	if len(hunkable) > 0 && ui.Offer("Pick individual hunks in the modified files?") {
+		if err := stageInteractively(hunkable); err != nil {
+			return fmt.Errorf("interactive staging failed: %w", err)
+		}
+	} else {
+		whole = append(whole, hunkable...)
+	}
+
+	if len(whole) == 0 {
+		return nil
+	}
+	if len(whole) == len(files) {
+		return StageAll()
+	}
+
+	root, err := RepoRoot()
+	if err != nil {
+		return err
+	}
+	// Porcelain paths are relative to the repo root
+	abs := make([]string, len(whole))
+	for i, p := range whole {
+		abs[i] = filepath.Join(root, p)
+	}
+	return StageFiles(abs)
+}
@@ -2,45 +2,218 @@ package gitflow
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
 )
 
-// Struct to hold the active task state (intent, branch, suggested subject)
+// Struct to hold the active task state (intent, branch, issue, suggested
+// subject) and its lifecycle: commits made through devgod and the PR.
 type ActiveTask struct {
 	Intent           string `json:"intent"`
 	Branch           string `json:"branch"`
+	IssueID          string `json:"issue_id,omitempty"`
 	SuggestedSubject string `json:"suggested_subject"`
+
+	BaseBranch string    `json:"base_branch,omitempty"`
+	Worktree   string    `json:"worktree,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitzero"`
+	UpdatedAt  time.Time `json:"updated_at,omitzero"`
+
+	// KeepWorktree records that the user chose to keep the worktree when
+	// offered its removal, so they are not asked again.
+	KeepWorktree bool `json:"keep_worktree,omitempty"`
+
+	Commits []TaskCommit `json:"commits,omitempty"`
+
+	// SquashedCommits are the commits replaced by the last `devgod squash`,
+	// put back by `devgod squash --undo`.
+	SquashedCommits []TaskCommit `json:"squashed_commits,omitempty"`
+
+	// CoAuthors are "Name <email>" identities added as Co-authored-by
+	// trailers to the task's commits.
+	CoAuthors []string `json:"co_authors,omitempty"`
+
+	PRNumber int    `json:"pr_number,omitempty"`
+	PRURL    string `json:"pr_url,omitempty"`
+	PRState  string `json:"pr_state,omitempty"`
+
+	ArchivedAt time.Time `json:"archived_at,omitzero"`
+}
+
+// Struct to hold a commit created through devgod for a task
+type TaskCommit struct {
+	Hash      string    `json:"hash"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// touch marks the task as updated now.
+func (t *ActiveTask) touch() {
+	t.UpdatedAt = time.Now()
+}
+
+// recordCommit appends a commit made through devgod to the task.
+func (t *ActiveTask) recordCommit(hash, message string) {
+	subject := strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+	t.Commits = append(t.Commits, TaskCommit{
+		Hash:      hash,
+		Subject:   subject,
+		CreatedAt: time.Now(),
+	})
+	t.SuggestedSubject = subject
+	t.touch()
 }
 
-// Struct to hold the repository state
+// replaceCommit swaps a recorded commit for its amended version, or records
+// the amended commit when the original was not made through devgod.
+func (t *ActiveTask) replaceCommit(oldHash, hash, message string) {
+	for i := range t.Commits {
+		if t.Commits[i].Hash == oldHash {
+			t.Commits = append(t.Commits[:i], t.Commits[i+1:]...)
+			break
+		}
+	}
+	t.recordCommit(hash, message)
+}
+
+// Struct to hold the repository state. Tasks are keyed by branch name so
+// several tasks can be in flight in the same repo.
 type RepoState struct {
+	Version int                    `json:"version"`
+	Tasks   map[string]*ActiveTask `json:"tasks,omitempty"`
+
+	// History holds finished tasks archived by `devgod done`, oldest first.
+	History []*ActiveTask `json:"history,omitempty"`
+
+	// ActiveTask is the legacy single-task field. LoadState migrates it
+	// into Tasks; it is never written back.
 	ActiveTask *ActiveTask `json:"active_task,omitempty"`
 }
 
+// AddTask stores the task under its branch, replacing any previous task
+// for the same branch.
+func (s *RepoState) AddTask(task *ActiveTask) {
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]*ActiveTask)
+	}
+	s.Tasks[task.Branch] = task
+}
+
+// TaskForBranch returns the task for the given branch, or nil.
+func (s *RepoState) TaskForBranch(branch string) *ActiveTask {
+	return s.Tasks[branch]
+}
+
+// RemoveTask forgets the task for the given branch.
+func (s *RepoState) RemoveTask(branch string) {
+	delete(s.Tasks, branch)
+}
+
+// ArchiveTask moves the task for branch from Tasks into History.
+func (s *RepoState) ArchiveTask(branch string) {
+	task := s.TaskForBranch(branch)
+	if task == nil {
+		return
+	}
+	now := time.Now()
+	task.ArchivedAt = now
+	task.UpdatedAt = now
+	s.History = append(s.History, task)
+	s.RemoveTask(branch)
+}
+
+// SortedTasks returns all tasks ordered by branch name.
+func (s *RepoState) SortedTasks() []*ActiveTask {
+	tasks := make([]*ActiveTask, 0, len(s.Tasks))
+	for _, t := range s.Tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Branch < tasks[j].Branch
+	})
+	return tasks
+}
+
+// Current schema version of the state file.
+//
+//	0/1: single "active_task"
+//	2:   "tasks" keyed by branch
+const stateVersion = 2
+
+// How long to wait for another devgod process to release the state lock.
+const stateLockTimeout = 10 * time.Second
+
+// stateMigrations[n] upgrades a state from version n to n+1.
+var stateMigrations = map[int]func(*RepoState){
+	0: func(s *RepoState) {}, // unversioned files are the v1 layout
+	1: migrateSingleTask,
+}
+
+// migrateSingleTask moves the legacy single ActiveTask into the Tasks map.
+func migrateSingleTask(s *RepoState) {
+	if s.ActiveTask != nil {
+		if s.TaskForBranch(s.ActiveTask.Branch) == nil {
+			s.AddTask(s.ActiveTask)
+		}
+		s.ActiveTask = nil
+	}
+}
+
+// migrate upgrades the state to the current schema version.
+func (s *RepoState) migrate() error {
+	if s.Version > stateVersion {
+		return fmt.Errorf("devgod state has version %d but this devgod only understands up to %d; please upgrade devgod", s.Version, stateVersion)
+	}
+	for s.Version < stateVersion {
+		if m, ok := stateMigrations[s.Version]; ok {
+			m(s)
+		}
+		s.Version++
+	}
+	return nil
+}
+
 // Returns the file path for storing the repo state.
 func stateFilePath() (string, error) {
-	root, err := RepoRoot()
+	dir, err := GitCommonDir()
 	if err != nil {
 		return "", err
 	}
-	// Store state in .git/devgod-state.json
-	return filepath.Join(root, ".git", "devgod-state.json"), nil
+	// Store state in the shared .git dir so every worktree sees the same tasks
+	return filepath.Join(dir, "devgod-state.json"), nil
+}
+
+// acquireStateLock takes the state lock. A dry run never writes the state,
+// so it skips the lock rather than create the lock file.
+func acquireStateLock(path string) (func(), error) {
+	if dryRun {
+		return func() {}, nil
+	}
+	return lockState(path)
 }
 
-// Writes the repository state to a file.
+// Writes the repository state to a file, holding the state lock.
 func SaveState(state *RepoState) error {
 	path, err := stateFilePath()
 	if err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(state, "", "  ")
+	unlock, err := acquireStateLock(path)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	return os.WriteFile(path, data, 0644)
+	return writeState(path, state)
 }
 
 // Loads the repository state from a file.
@@ -49,12 +222,131 @@ func LoadState() (*RepoState, error) {
 	if err != nil {
 		return nil, err
 	}
+	return readState(path)
+}
 
-	data, err := os.ReadFile(path)
+// UpdateState runs a read-modify-write cycle on the state file while holding
+// the state lock, so concurrent devgod invocations cannot lose each other's
+// changes. If fn returns an error nothing is written.
+func UpdateState(fn func(*RepoState) error) error {
+	path, err := stateFilePath()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := acquireStateLock(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &RepoState{}, nil
+		return err
+	}
+	defer unlock()
+
+	state, err := readState(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(state); err != nil {
+		return err
+	}
+	return writeState(path, state)
+}
+
+// UpdateTask applies fn to the task for branch inside UpdateState.
+// It is a no-op when the branch has no task.
+func UpdateTask(branch string, fn func(*ActiveTask)) error {
+	return UpdateState(func(s *RepoState) error {
+		if task := s.TaskForBranch(branch); task != nil {
+			fn(task)
 		}
+		return nil
+	})
+}
+
+// writeState atomically replaces the state file: the previous good copy is
+// kept as a backup, and the new data is written to a temp file, synced and
+// renamed over the old one so readers never see a partial write.
+func writeState(path string, state *RepoState) error {
+	state.Version = stateVersion
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		printStatePlan(path, data)
+		return nil
+	}
+
+	// Keep the last good state around for recovery
+	if prev, err := os.ReadFile(path); err == nil && json.Valid(prev) {
+		_ = os.WriteFile(path+".bak", prev, 0644)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, path)
+}
+
+// readState loads and migrates the state file. A corrupt file is moved
+// aside and the backup from the previous write is used instead.
+func readState(path string) (*RepoState, error) {
+	state, err := parseStateFile(path)
+	if err == nil || os.IsNotExist(err) {
+		if state == nil {
+			state = &RepoState{}
+			_ = state.migrate()
+		}
+		return state, nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+
+	corrupt := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+	if !planned("mv", path, corrupt) {
+		_ = os.Rename(path, corrupt)
+	}
+	fmt.Println(ui.Yellow("⚠️ devgod state was corrupted:"), err)
+	fmt.Println("   Saved the broken file as", corrupt)
+
+	backup, bakErr := parseStateFile(path + ".bak")
+	if bakErr != nil {
+		fmt.Println("   No usable backup found; starting with empty state.")
+		state := &RepoState{}
+		_ = state.migrate()
+		return state, nil
+	}
+
+	fmt.Println("   Recovered state from the previous backup.")
+	return backup, nil
+}
+
+func parseStateFile(path string) (*RepoState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
 
@@ -63,5 +355,8 @@ func LoadState() (*RepoState, error) {
 	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, err
 	}
+	if err := state.migrate(); err != nil {
+		return nil, err
+	}
 	return &state, nil
 }
@@ -0,0 +1,41 @@
+//go:build !unix
+
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed to be
+// left over from a crashed process.
+const staleLockAge = time.Minute
+
+// lockState takes an exclusive lock by creating <path>.lock, waiting up to
+// stateLockTimeout for other devgod processes to remove it.
+func lockState(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("devgod state is locked by another devgod process (remove %s if none is running)", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
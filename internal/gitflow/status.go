@@ -0,0 +1,234 @@
+package gitflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// PRStatus is the subset of `gh pr view --json` shown by `devgod status`.
+type PRStatus struct {
+	Number         int    `json:"number"`
+	URL            string `json:"url"`
+	State          string `json:"state"`
+	IsDraft        bool   `json:"isDraft"`
+	ReviewDecision string `json:"reviewDecision"`
+	Checks         []struct {
+		Name       string `json:"name"`
+		Context    string `json:"context"`
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+		State      string `json:"state"`
+	} `json:"statusCheckRollup"`
+}
+
+// fetchPRStatus asks gh for the PR of the given branch.
+func fetchPRStatus(branch string) (*PRStatus, error) {
+	cmd := exec.Command("gh", "pr", "view", branch, "--json", "number,url,state,isDraft,reviewDecision,statusCheckRollup")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &PRStatus{}
+	if err := json.Unmarshal(out, pr); err != nil {
+		return nil, fmt.Errorf("failed to parse gh pr view output: %w", err)
+	}
+	return pr, nil
+}
+
+// summarizeChecks counts passing, failing and pending CI checks.
+func (pr *PRStatus) summarizeChecks() (passed, failed, pending int) {
+	for _, c := range pr.Checks {
+		// CheckRun entries use status/conclusion, StatusContext entries use state.
+		result := strings.ToUpper(c.Conclusion)
+		if result == "" {
+			result = strings.ToUpper(c.State)
+		}
+		if strings.ToUpper(c.Status) != "" && strings.ToUpper(c.Status) != "COMPLETED" {
+			result = "PENDING"
+		}
+
+		switch result {
+		case "SUCCESS", "NEUTRAL", "SKIPPED":
+			passed++
+		case "PENDING", "EXPECTED", "":
+			pending++
+		default:
+			failed++
+		}
+	}
+	return passed, failed, pending
+}
+
+// taskBaseRef returns the ref to compare the task branch against,
+// preferring the remote-tracking branch when it exists.
+func taskBaseRef(base string) string {
+	if base == "" {
+		return ""
+	}
+	if RefExists("refs/remotes/origin/" + base) {
+		return "origin/" + base
+	}
+	if RefExists(base) {
+		return base
+	}
+	return ""
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// Status prints the current task, its position relative to the base branch,
+// uncommitted changes, push state and PR/CI status in one screen.
+func Status() error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	branch, err := CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	task := state.TaskForBranch(branch)
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("📊 DEVGOD STATUS"))
+
+	fmt.Println("🌿 " + ui.BranchLabelStyle.Render("Branch:"))
+	fmt.Println("   " + ui.ValueStyle.Render(branch))
+	fmt.Println()
+
+	if task == nil {
+		fmt.Println("🎯 " + ui.IntentLabelStyle.Render("Task:"))
+		fmt.Println("   " + ui.Dim("(no devgod task on this branch)"))
+		if n := len(state.Tasks); n > 0 {
+			fmt.Printf("   %s\n", ui.Dim(fmt.Sprintf("%d other task(s); see `devgod tasks list`", n)))
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("🎯 " + ui.IntentLabelStyle.Render("Task:"))
+		fmt.Println("   " + ui.ValueStyle.Render(task.Intent))
+		if task.IssueID != "" {
+			fmt.Println("   " + ui.Dim("issue: "+task.IssueID))
+		}
+		fmt.Println("   " + ui.Dim(fmt.Sprintf("started %s, updated %s", formatAge(task.CreatedAt), formatAge(task.UpdatedAt))))
+		fmt.Println()
+
+		if len(task.Commits) > 0 {
+			fmt.Println("✍️  " + ui.CommitLabelStyle.Render("Commits made with devgod:"))
+			for _, c := range task.Commits {
+				fmt.Printf("   %s %s\n", ui.Dim(shortHash(c.Hash)), ui.ValueStyle.Render(c.Subject))
+			}
+			fmt.Println()
+		}
+	}
+
+	// Position relative to base
+	fmt.Println("🧱 " + ui.SectionTitleStyle.Render("Base:"))
+	base := ""
+	if task != nil {
+		base = task.BaseBranch
+	}
+	if ref := taskBaseRef(base); ref == "" {
+		fmt.Println("   " + ui.Dim("(unknown base branch)"))
+	} else if ahead, behind, err := AheadBehind(ref, "HEAD"); err != nil {
+		fmt.Println("   " + ui.Yellow("could not compare with "+ref))
+	} else {
+		fmt.Printf("   %s: %d ahead, %d behind\n", ui.ValueStyle.Render(ref), ahead, behind)
+	}
+	fmt.Println()
+
+	// Working tree
+	fmt.Println("📦 " + ui.SectionTitleStyle.Render("Working tree:"))
+	summary, _ := WorkingTreeStatus()
+	if strings.TrimSpace(summary) == "" {
+		fmt.Println("   " + ui.Green("clean"))
+	} else {
+		for _, line := range strings.Split(summary, "\n") {
+			if strings.TrimSpace(line) != "" {
+				fmt.Println("   " + ui.ColorizeStatusLine(line))
+			}
+		}
+	}
+	fmt.Println()
+
+	// Push state
+	fmt.Println("📤 " + ui.SectionTitleStyle.Render("Remote:"))
+	if upstream := UpstreamBranch(); upstream == "" {
+		fmt.Println("   " + ui.Yellow("not pushed"))
+	} else if ahead, behind, err := AheadBehind(upstream, "HEAD"); err != nil {
+		fmt.Println("   " + ui.ValueStyle.Render(upstream))
+	} else if ahead == 0 && behind == 0 {
+		fmt.Printf("   %s %s\n", ui.ValueStyle.Render(upstream), ui.Green("up to date"))
+	} else {
+		fmt.Printf("   %s: %d to push, %d to pull\n", ui.ValueStyle.Render(upstream), ahead, behind)
+	}
+	fmt.Println()
+
+	// PR + CI
+	fmt.Println("🚀 " + ui.SectionTitleStyle.Render("Pull request:"))
+	if !ghInstalled() {
+		fmt.Println("   " + ui.Dim("(gh not installed)"))
+	} else if pr, err := fetchPRStatus(branch); err != nil {
+		fmt.Println("   " + ui.Dim("(no PR found)"))
+	} else {
+		label := pr.State
+		if pr.IsDraft {
+			label += ", draft"
+		}
+		if pr.ReviewDecision != "" {
+			label += ", " + strings.ToLower(strings.ReplaceAll(pr.ReviewDecision, "_", " "))
+		}
+		fmt.Printf("   #%d %s (%s)\n", pr.Number, ui.ValueStyle.Render(pr.URL), label)
+
+		if len(pr.Checks) == 0 {
+			fmt.Println("   " + ui.Dim("CI: no checks"))
+		} else {
+			passed, failed, pending := pr.summarizeChecks()
+			ci := fmt.Sprintf("CI: %d passed, %d failed, %d pending", passed, failed, pending)
+			switch {
+			case failed > 0:
+				fmt.Println("   " + ui.Red(ci))
+			case pending > 0:
+				fmt.Println("   " + ui.Yellow(ci))
+			default:
+				fmt.Println("   " + ui.Green(ci))
+			}
+		}
+	}
+
+	fmt.Println(ui.Divider.Render(strings.Repeat("─", 45)))
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,446 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// Ways `devgod sync` can bring a task branch up to date with its base.
+const (
+	syncRebase = "rebase"
+	syncMerge  = "merge"
+)
+
+// SyncOptions holds optional inputs for Sync.
+type SyncOptions struct {
+	// Strategy is "rebase" or "merge". When empty it comes from
+	// `git config devgod.sync.strategy`, defaulting to rebase.
+	Strategy string
+
+	// Continue resumes a sync that stopped on conflicts.
+	Continue bool
+
+	// Abort cancels a sync that stopped on conflicts.
+	Abort bool
+}
+
+// syncStrategy resolves the strategy from the flag, then git config.
+func syncStrategy(flag string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(flag))
+	if s == "" {
+		s = strings.ToLower(configValue("sync.strategy"))
+	}
+	switch s {
+	case "", syncRebase:
+		return syncRebase, nil
+	case syncMerge:
+		return syncMerge, nil
+	default:
+		return "", fmt.Errorf("unknown sync strategy %q; use rebase or merge", s)
+	}
+}
+
+// syncInProgress reports which operation ("rebase" or "merge") stopped
+// midway in this worktree, or "".
+func syncInProgress() string {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if p, err := shell.Run("git", "rev-parse", "--git-path", dir); err == nil {
+			if _, err := os.Stat(strings.TrimSpace(p)); err == nil {
+				return syncRebase
+			}
+		}
+	}
+	if RefExists("MERGE_HEAD") {
+		return syncMerge
+	}
+	return ""
+}
+
+// rebasingBranch returns the branch a stopped rebase is rewriting, or "".
+func rebasingBranch() string {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		p, err := shell.Run("git", "rev-parse", "--git-path", dir+"/head-name")
+		if err != nil {
+			continue
+		}
+		if data, err := os.ReadFile(strings.TrimSpace(p)); err == nil {
+			return strings.TrimPrefix(strings.TrimSpace(string(data)), "refs/heads/")
+		}
+	}
+	return ""
+}
+
+// conflictedFiles lists unmerged paths relative to the repo root.
+func conflictedFiles() []string {
+	out, err := shell.Run("git", "diff", "--name-only", "--diff-filter=U", "-z")
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, f := range strings.Split(out, "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// gitNoEditor runs git with any editor prompts auto-accepted, so rebase and
+// merge keep their default messages.
+func gitNoEditor(args ...string) (string, error) {
+	if planned("git", args...) {
+		return "", nil
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("command failed: git %s\n%w\noutput:\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out), nil
+}
+
+// Sync fetches the task's base branch and rebases (or merges) the task
+// branch onto it, walking the user through any conflicts.
+func Sync(opts SyncOptions) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	task, err := currentTask()
+	if err != nil {
+		return err
+	}
+
+	// Resume or abort a sync that stopped on conflicts
+	if op := syncInProgress(); op != "" {
+		switch {
+		case opts.Abort:
+			return abortSync(op)
+		case opts.Continue:
+			return resumeSync(task, op)
+		default:
+			return fmt.Errorf("a %s is already in progress; run `devgod sync --continue` or `devgod sync --abort`", op)
+		}
+	}
+	if opts.Continue || opts.Abort {
+		return fmt.Errorf("no sync in progress")
+	}
+
+	strategy, err := syncStrategy(opts.Strategy)
+	if err != nil {
+		return err
+	}
+
+	baseBranch := task.BaseBranch
+	if baseBranch == "" {
+		baseBranch = resolveBaseBranch("")
+	}
+	if baseBranch == "" {
+		return fmt.Errorf("don't know which branch %s is based on; set `git config devgod.base <branch>`", task.Branch)
+	}
+
+	if HasRemote("origin") {
+		stop := ui.StartSpinner(fmt.Sprintf("Fetching latest %s from origin...", baseBranch))
+		err := FetchOrigin(baseBranch)
+		stop()
+		if err != nil {
+			fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ Could not fetch %s from origin; using the local copy.", baseBranch)))
+		}
+	}
+	upstream := taskBaseRef(baseBranch)
+	if upstream == "" {
+		return fmt.Errorf("base branch %s not found", baseBranch)
+	}
+
+	ahead, behind, err := AheadBehind(upstream, "HEAD")
+	if err != nil {
+		return err
+	}
+	if behind == 0 {
+		fmt.Println(ui.Green(fmt.Sprintf("✔️ %s is already up to date with %s.", task.Branch, upstream)))
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println("🌿 " + ui.BranchLabelStyle.Render("Branch:"))
+	fmt.Println("   " + ui.ValueStyle.Render(task.Branch))
+	fmt.Println("   " + ui.Dim(fmt.Sprintf("%d commit(s) ahead, %d behind %s", ahead, behind, upstream)))
+	fmt.Println()
+
+	if !ui.Confirm(fmt.Sprintf("%s %s onto %s?", strings.ToUpper(strategy[:1])+strategy[1:], task.Branch, upstream)) {
+		fmt.Println("❌ Sync cancelled.")
+		return nil
+	}
+
+	args := []string{"rebase", "--autostash"}
+	if strategy == syncMerge {
+		args = []string{"merge", "--autostash", "--no-edit"}
+	}
+	args = append(append(args, signArgs()...), upstream)
+	out, err := gitNoEditor(args...)
+	if err != nil {
+		if len(conflictedFiles()) == 0 {
+			// Not a conflict; leave the branch as it was
+			if syncInProgress() != "" {
+				_ = abortSync(strategy)
+			}
+			return fmt.Errorf("%s failed: %w", strategy, err)
+		}
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ The %s stopped on conflicts.", strategy)))
+		return resumeSync(task, strategy)
+	}
+	if strings.TrimSpace(out) != "" {
+		fmt.Println(ui.Dim(strings.TrimSpace(out)))
+	}
+
+	return finishSync(task, strategy, upstream)
+}
+
+// resumeSync resolves conflicts and continues the rebase or merge until it
+// completes, the user stops, or it aborts.
+func resumeSync(task *ActiveTask, op string) error {
+	for {
+		if files := conflictedFiles(); len(files) > 0 {
+			done, err := walkConflicts(files, op)
+			if err != nil || !done {
+				return err
+			}
+		}
+
+		var out string
+		var err error
+		if op == syncRebase {
+			out, err = gitNoEditor("rebase", "--continue")
+			// A commit whose changes were all resolved away has nothing left
+			if err != nil && len(conflictedFiles()) == 0 && strings.Contains(out, "nothing to commit") {
+				out, err = gitNoEditor("rebase", "--skip")
+			}
+		} else {
+			out, err = gitNoEditor(append([]string{"commit", "--no-edit"}, signArgs()...)...)
+		}
+
+		if err != nil {
+			// The next commit in the rebase conflicted too
+			if len(conflictedFiles()) > 0 {
+				fmt.Println(ui.Yellow("⚠️ The next commit has conflicts as well."))
+				continue
+			}
+			return fmt.Errorf("could not continue the %s: %w", op, err)
+		}
+		if syncInProgress() == "" {
+			break
+		}
+		if strings.TrimSpace(out) != "" {
+			fmt.Println(ui.Dim(strings.TrimSpace(out)))
+		}
+	}
+
+	return finishSync(task, op, taskBaseRef(task.BaseBranch))
+}
+
+// abortSync puts the branch back as it was before the sync started.
+func abortSync(op string) error {
+	if _, err := mutate("git", op, "--abort"); err != nil {
+		return fmt.Errorf("failed to abort the %s: %w", op, err)
+	}
+	fmt.Println(ui.Yellow(fmt.Sprintf("↩️ %s aborted; the branch is back where it was.", strings.ToUpper(op[:1])+op[1:])))
+	return nil
+}
+
+// finishSync reports success and offers to push the updated branch.
+func finishSync(task *ActiveTask, op, upstream string) error {
+	if dryRun {
+		fmt.Println(ui.Dim("The commands above would bring " + task.Branch + " up to date."))
+	} else if upstream != "" {
+		fmt.Println(ui.Green(fmt.Sprintf("✔️ %s is up to date with %s.", task.Branch, upstream)))
+	} else {
+		fmt.Println(ui.Green(fmt.Sprintf("✔️ %s finished.", strings.ToUpper(op[:1])+op[1:])))
+	}
+
+	if err := UpdateTask(task.Branch, func(t *ActiveTask) { t.touch() }); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+	}
+
+	if !IsBranchPushed(task.Branch) {
+		return nil
+	}
+
+	// A rebase rewrote published commits; a merge only adds to them
+	if op == syncRebase {
+		if !ui.Confirm(fmt.Sprintf("Force-push %s to origin (with lease)?", task.Branch)) {
+			fmt.Println("Push later with: git push --force-with-lease")
+			return nil
+		}
+		if err := ForcePushBranch(task.Branch); err != nil {
+			return fmt.Errorf("failed to push branch: %w", err)
+		}
+	} else {
+		if !ui.Confirm(fmt.Sprintf("Push %s to origin?", task.Branch)) {
+			return nil
+		}
+		if err := PushBranch(task.Branch); err != nil {
+			return fmt.Errorf("failed to push branch: %w", err)
+		}
+	}
+	if !dryRun {
+		fmt.Println(ui.Green("✔️ Branch pushed to origin."))
+	}
+	return nil
+}
+
+const (
+	conflictAI     = "Let AI suggest a resolution for each hunk"
+	conflictEdit   = "Open it in your editor"
+	conflictMine   = "Keep your version"
+	conflictBase   = "Keep the base branch's version"
+	conflictMarked = "Mark it resolved (I fixed it myself)"
+	conflictStop   = "Stop here and finish by hand"
+	conflictAbort  = "Abort the sync"
+)
+
+// walkConflicts goes through the conflicted files one by one. It returns
+// true when every file is resolved and staged, false when the user stopped
+// or aborted.
+func walkConflicts(files []string, op string) (bool, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return false, err
+	}
+
+	for i, f := range files {
+		for {
+			fmt.Println()
+			fmt.Println("⚔️  " + ui.SectionTitleStyle.Render(fmt.Sprintf("Conflict %d of %d:", i+1, len(files))))
+			fmt.Println("   " + ui.ValueStyle.Render(f))
+			if n := countConflictMarkers(filepath.Join(root, f)); n > 0 {
+				fmt.Println("   " + ui.Dim(fmt.Sprintf("%d conflicting hunk(s)", n)))
+			}
+			fmt.Println()
+
+			actions := []string{conflictAI, conflictEdit, conflictMine, conflictBase, conflictMarked, conflictStop, conflictAbort}
+			for j, a := range actions {
+				fmt.Printf("  %2d) %s\n", j+1, a)
+			}
+			action, err := ui.SelectOne(actions, ui.Cyan("How do you want to resolve it?"))
+			if err != nil {
+				return false, err
+			}
+
+			resolved := false
+			switch action {
+			case conflictAI:
+				resolved = resolveWithAI(root, f, op)
+			case conflictEdit:
+				if err := openInEditor(filepath.Join(root, f)); err != nil {
+					fmt.Println(ui.Yellow("⚠️ Could not open the editor:"), err)
+					continue
+				}
+				resolved = markResolved(root, f)
+			case conflictMine:
+				resolved = takeSide(root, f, mineSide(op))
+			case conflictBase:
+				resolved = takeSide(root, f, baseSide(op))
+			case conflictMarked:
+				resolved = markResolved(root, f)
+			case conflictStop:
+				fmt.Println("Resolve the remaining files, `git add` them, then run: devgod sync --continue")
+				fmt.Println("Or give up with: devgod sync --abort")
+				return false, nil
+			case conflictAbort:
+				return false, abortSync(op)
+			}
+
+			if resolved {
+				fmt.Println(ui.Green("✔️ Resolved:"), f)
+				break
+			}
+		}
+	}
+	return true, nil
+}
+
+// mineSide is the checkout flag for the task branch's side. During a rebase
+// git replays your commits onto the base, so "ours" is the base.
+func mineSide(op string) string {
+	if op == syncRebase {
+		return "--theirs"
+	}
+	return "--ours"
+}
+
+// baseSide is the checkout flag for the base branch's side.
+func baseSide(op string) string {
+	if op == syncRebase {
+		return "--ours"
+	}
+	return "--theirs"
+}
+
+// takeSide resolves a file by taking one side wholesale. If that side
+// deleted the file, the deletion is staged.
+func takeSide(root, file, side string) bool {
+	if _, err := mutate("git", "-C", root, "checkout", side, "--", file); err != nil {
+		if _, rmErr := mutate("git", "-C", root, "rm", "-q", "--", file); rmErr != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not take that version:"), err)
+			return false
+		}
+		return true
+	}
+	if _, err := mutate("git", "-C", root, "add", "--", file); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not stage the file:"), err)
+		return false
+	}
+	return true
+}
+
+// markResolved stages the file once no conflict markers are left in it.
+func markResolved(root, file string) bool {
+	if n := countConflictMarkers(filepath.Join(root, file)); n > 0 {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ %s still has %d conflict marker block(s).", file, n)))
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(root, file)); os.IsNotExist(err) {
+		_, err = mutate("git", "-C", root, "rm", "-q", "--", file)
+		return err == nil
+	}
+	if _, err := mutate("git", "-C", root, "add", "--", file); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not stage the file:"), err)
+		return false
+	}
+	return true
+}
+
+// countConflictMarkers counts "<<<<<<<" blocks left in a file.
+func countConflictMarkers(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || line == "<<<<<<<" {
+			n++
+		}
+	}
+	return n
+}
+
+// openInEditor opens the file in git's configured editor.
+func openInEditor(path string) error {
+	editor, err := shell.Run("git", "var", "GIT_EDITOR")
+	if err != nil || strings.TrimSpace(editor) == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command("sh", "-c", strings.TrimSpace(editor)+` "$@"`, "editor", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
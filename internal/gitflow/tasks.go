@@ -0,0 +1,307 @@
+package gitflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// RenameBranch renames a local branch.
+func RenameBranch(oldName, newName string) error {
+	_, err := mutate("git", "branch", "-m", oldName, newName)
+	return err
+}
+
+// resolveTaskForCommit returns the task for the current branch. When the
+// current branch has no task it offers to switch to one of the known tasks.
+// Returns (nil, nil) when the user cancels.
+func resolveTaskForCommit(state *RepoState) (*ActiveTask, error) {
+	if len(state.Tasks) == 0 {
+		return nil, fmt.Errorf("no active task found")
+	}
+
+	currentBranch, err := CurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	if task := state.TaskForBranch(currentBranch); task != nil {
+		return task, nil
+	}
+
+	fmt.Println(ui.Yellow("⚠️ You are NOT on a branch for any devgod task."))
+	fmt.Println("Current branch: ", currentBranch)
+	fmt.Println()
+
+	task, err := pickTask(state, "Switch to which task? Select by number:")
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Worktree != "" {
+		fmt.Println(ui.Yellow("This task lives in its own worktree. Run:"))
+		fmt.Println("  cd", task.Worktree)
+		fmt.Println("and then retry.")
+		return nil, nil
+	}
+
+	if !ui.Confirm(fmt.Sprintf("Switch to %s now?", task.Branch)) {
+		fmt.Println(ui.Red("Commit cancelled. Switch to the correct branch and try again."))
+		return nil, nil
+	}
+
+	if err := CheckoutBranch(task.Branch); err != nil {
+		fmt.Println(ui.Red("❌ Failed to switch branches automatically."))
+		fmt.Println("Please run:")
+		fmt.Println("  git checkout", task.Branch)
+		fmt.Println("and then retry.")
+		return nil, err
+	}
+
+	fmt.Println(ui.Green("✔️ Switched to the correct branch."))
+	fmt.Println()
+	return task, nil
+}
+
+// pickTask lists the tasks and lets the user choose one.
+func pickTask(state *RepoState, prompt string) (*ActiveTask, error) {
+	tasks := state.SortedTasks()
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+	if len(tasks) == 1 {
+		return tasks[0], nil
+	}
+
+	branches := make([]string, len(tasks))
+	fmt.Println(ui.Green("Tasks:"))
+	for i, t := range tasks {
+		branches[i] = t.Branch
+		fmt.Printf("  %2d) %s  %s\n", i+1, t.Branch, ui.Dim(t.Intent))
+	}
+	fmt.Println()
+
+	selected, err := ui.SelectOne(branches, ui.Cyan(prompt))
+	if err != nil {
+		return nil, err
+	}
+	return state.TaskForBranch(selected), nil
+}
+
+// taskByBranchOrAsk returns the task for branch, or asks the user to pick
+// one when branch is empty.
+func taskByBranchOrAsk(state *RepoState, branch, prompt string) (*ActiveTask, error) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return pickTask(state, prompt)
+	}
+
+	task := state.TaskForBranch(branch)
+	if task == nil {
+		return nil, fmt.Errorf("no task found for branch %s", branch)
+	}
+	return task, nil
+}
+
+// ListTasks prints every task tracked in this repo, marking the one for
+// the current branch.
+func ListTasks() error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	tasks := state.SortedTasks()
+	if len(tasks) == 0 {
+		fmt.Println("No tasks yet. Start one with: devgod git \"your intent\"")
+		return nil
+	}
+
+	current, _ := CurrentBranch()
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("📋 DEVGOD TASKS"))
+	for _, t := range tasks {
+		marker := "  "
+		branch := ui.ValueStyle.Render(t.Branch)
+		if t.Branch == current {
+			marker = ui.Green("➜ ")
+			branch = ui.Green(t.Branch)
+		}
+		fmt.Println(marker + branch)
+		fmt.Println("    " + ui.Dim(t.Intent))
+		if t.Worktree != "" {
+			fmt.Println("    " + ui.Dim("worktree: "+t.Worktree))
+		}
+		if t.IssueID != "" {
+			fmt.Println("    " + ui.Dim("issue: "+t.IssueID))
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// SwitchTask checks out the branch of another task.
+func SwitchTask(branch string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	task, err := taskByBranchOrAsk(state, branch, "Switch to which task? Select by number:")
+	if err != nil {
+		return err
+	}
+
+	if task.Worktree != "" {
+		fmt.Println(ui.Yellow("This task lives in its own worktree. Run:"))
+		fmt.Println("  cd", task.Worktree)
+		return nil
+	}
+
+	if err := CheckoutBranch(task.Branch); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Green("✔️ Switched to task:"), task.Branch)
+	fmt.Println("   " + ui.Dim(task.Intent))
+	return nil
+}
+
+// DropTask forgets a task. The branch itself is left untouched.
+func DropTask(branch string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	task, err := taskByBranchOrAsk(state, branch, "Drop which task? Select by number:")
+	if err != nil {
+		return err
+	}
+
+	if !ui.Confirm(fmt.Sprintf("Stop tracking task %s? (the branch is kept)", task.Branch)) {
+		fmt.Println("❌ Drop cancelled.")
+		return nil
+	}
+
+	offerWorktreeRemoval(task, fmt.Sprintf("Also remove its worktree %s?", task.Worktree))
+
+	err = UpdateState(func(s *RepoState) error {
+		s.RemoveTask(task.Branch)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Green("✔️ Task dropped:"), task.Branch)
+	return nil
+}
+
+// RenameTask renames a task's branch and keeps the task attached to it.
+func RenameTask(oldBranch, newBranch string) error {
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	newBranch = strings.TrimSpace(newBranch)
+	if err := LoadBranchPolicy().Validate(newBranch); err != nil {
+		return err
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	// Default to the task for the current branch
+	if strings.TrimSpace(oldBranch) == "" {
+		if current, err := CurrentBranch(); err == nil && state.TaskForBranch(current) != nil {
+			oldBranch = current
+		}
+	}
+
+	task, err := taskByBranchOrAsk(state, oldBranch, "Rename which task? Select by number:")
+	if err != nil {
+		return err
+	}
+	if state.TaskForBranch(newBranch) != nil {
+		return fmt.Errorf("a task for branch %s already exists", newBranch)
+	}
+
+	oldName := task.Branch
+	upstream := branchUpstream(oldName)
+	if err := RenameBranch(oldName, newBranch); err != nil {
+		return err
+	}
+	if err := moveSquashBackup(oldName, newBranch); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not move the squash backup:"), err)
+	}
+
+	err = UpdateState(func(s *RepoState) error {
+		t := s.TaskForBranch(oldName)
+		if t == nil {
+			return fmt.Errorf("task for branch %s disappeared", oldName)
+		}
+		s.RemoveTask(oldName)
+		t.Branch = newBranch
+		t.touch()
+		s.AddTask(t)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Green("✔️ Task renamed:"), oldName, "→", newBranch)
+
+	// git keeps the old upstream and worktree directory; say how to follow
+	if upstream != "" {
+		fmt.Println(ui.Yellow("⚠️ The branch still tracks " + upstream + ". To publish the new name, run:"))
+		fmt.Println("  git push -u origin " + newBranch)
+		fmt.Println("  git push origin --delete " + strings.TrimPrefix(upstream, "origin/"))
+	}
+	if task.Worktree != "" {
+		fmt.Println(ui.Dim("The worktree stays at " + task.Worktree + "; move it with `git worktree move` if you like."))
+	}
+	return nil
+}
+
+// branchUpstream returns the upstream of branch (e.g. "origin/feat/x"), or "".
+func branchUpstream(branch string) string {
+	out, err := shell.Run("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{u}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// moveSquashBackup moves the pre-squash backup of oldBranch, if any, so
+// `devgod squash --undo` keeps working after a rename.
+func moveSquashBackup(oldBranch, newBranch string) error {
+	oldRef := squashBackupRef(oldBranch)
+	out, err := shell.Run("git", "rev-parse", "--verify", "-q", oldRef)
+	if err != nil {
+		return nil
+	}
+	if _, err := mutate("git", "update-ref", squashBackupRef(newBranch), strings.TrimSpace(out)); err != nil {
+		return err
+	}
+	_, err = mutate("git", "update-ref", "-d", oldRef)
+	return err
+}
@@ -0,0 +1,115 @@
+package gitflow
+
+import (
+	"fmt"
+
+	"github.com/jeethsoni/devgod-cli/internal/tracker"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// trackerForIssue returns the configured tracker provider when issueID is a
+// tracker key (not a GitHub "#42" reference). Configuration problems are
+// reported as warnings so they never block the git flow.
+func trackerForIssue(issueID string) tracker.Provider {
+	if issueID == "" || isGitHubIssue(issueID) {
+		return nil
+	}
+
+	provider, err := tracker.FromEnv()
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️ Issue tracker is misconfigured:"), err)
+		return nil
+	}
+	return provider
+}
+
+// moveTicket transitions a tracker ticket, warning instead of failing.
+func moveTicket(provider tracker.Provider, key, state string) {
+	if plannedAction(fmt.Sprintf("move %s to %q in %s", key, state, provider.Name())) {
+		return
+	}
+	if err := provider.Transition(key, state); err != nil {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ Could not move %s to %q in %s:", key, state, provider.Name())), err)
+		return
+	}
+	fmt.Println(ui.Green(fmt.Sprintf("✔️ %s moved to %q in %s.", key, state, provider.Name())))
+}
+
+// markTicketInProgress moves the task's ticket to the in-progress state.
+func markTicketInProgress(issueID string) {
+	provider := trackerForIssue(issueID)
+	if provider == nil {
+		return
+	}
+	moveTicket(provider, issueID, tracker.StateFromEnv("DEVGOD_TRACKER_IN_PROGRESS", tracker.DefaultInProgressState))
+}
+
+// markTicketInReview moves the task's ticket to the in-review state and
+// posts the PR link on it.
+func markTicketInReview(issueID, prURL string) {
+	provider := trackerForIssue(issueID)
+	if provider == nil {
+		return
+	}
+
+	moveTicket(provider, issueID, tracker.StateFromEnv("DEVGOD_TRACKER_IN_REVIEW", tracker.DefaultInReviewState))
+
+	if prURL == "" {
+		return
+	}
+	if plannedAction(fmt.Sprintf("comment on %s: Pull request opened: %s", issueID, prURL)) {
+		return
+	}
+	if err := provider.AddComment(issueID, "Pull request opened: "+prURL); err != nil {
+		fmt.Println(ui.Yellow(fmt.Sprintf("⚠️ Could not post the PR link on %s:", issueID)), err)
+		return
+	}
+	fmt.Println(ui.Green(fmt.Sprintf("✔️ PR link posted on %s.", issueID)))
+}
+
+// StartTaskFromTicket starts a task seeded from an issue ID alone: GitHub
+// references go through gh, tracker keys through the configured provider.
+func StartTaskFromTicket(issueID string, opts StartOptions) error {
+	issueID = normalizeIssueID(issueID)
+	if isGitHubIssue(issueID) {
+		return StartTaskFromIssue(issueID, opts)
+	}
+
+	if !IsGitRepo() {
+		return fmt.Errorf("not inside a git repo")
+	}
+
+	provider, err := tracker.FromEnv()
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return fmt.Errorf("no issue tracker configured; set DEVGOD_TRACKER or pass an intent")
+	}
+
+	stop := ui.StartSpinner(fmt.Sprintf("Fetching %s from %s...", issueID, provider.Name()))
+	issue, err := provider.GetIssue(issueID)
+	stop()
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", issueID, err)
+	}
+
+	fmt.Println()
+	fmt.Println("🎫 " + ui.SectionTitleStyle.Render(issue.Key+":"))
+	fmt.Println("   " + ui.ValueStyle.Render(issue.Title))
+	if issue.Type != "" {
+		fmt.Println("   " + ui.Dim("type: "+issue.Type))
+	}
+	fmt.Println()
+
+	opts.IssueID = issue.Key
+	if opts.BranchType == "" {
+		opts.BranchType = branchTypeForLabels([]string{issue.Type})
+	}
+
+	intent := issueIntent(issue.Title, issue.Description)
+	if validateIntent(intent) != nil {
+		return fmt.Errorf("%s has too little text to start a task from; give the intent yourself: devgod git --issue %s \"<what you will do>\"", issue.Key, issue.Key)
+	}
+	return StartTask(intent, opts)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/jeethsoni/devgod-cli/internal/ai"
 	"github.com/jeethsoni/devgod-cli/internal/ui"
@@ -30,54 +31,271 @@ func validateIntent(intent string) error {
 	return nil
 }
 
+// StartOptions holds optional inputs for StartTask.
+type StartOptions struct {
+	// IssueID is a ticket reference like "ABC-123" or "#42". When empty,
+	// StartTask looks for one in the intent.
+	IssueID string
+
+	// BranchType forces the branch type (e.g. "fix") instead of letting the
+	// model pick one.
+	BranchType string
+
+	// Worktree creates the task branch in its own worktree directory
+	// instead of switching the current checkout.
+	Worktree bool
+
+	// BaseBranch is the branch to start from. When empty it comes from
+	// `git config devgod.base` or origin/HEAD.
+	BaseBranch string
+}
+
 // StartTask creates a new branch for the task based on the intent.
-func StartTask(intent string) error {
+func StartTask(intent string, opts StartOptions) error {
+	_, err := startTask(intent, opts)
+	return err
+}
+
+// startTask does the work of StartTask and reports whether a branch was
+// actually created (false when the user cancelled).
+func startTask(intent string, opts StartOptions) (bool, error) {
 	if !IsGitRepo() {
-		return fmt.Errorf("not inside a git repo")
+		return false, fmt.Errorf("not inside a git repo")
 	}
 
 	intent = strings.TrimSpace(intent)
 
 	if err := validateIntent(intent); err != nil {
-		return err
+		return false, err
 	}
 
-	// AI branch naming with loading dots
-	stop := ui.StartSpinner("🪄 Asking the dev gods for the perfect branch name...")
-	branchName, err := ai.GenerateBranchName(intent)
-	stop()
-	if err != nil {
-		return fmt.Errorf("failed to generate branch name: %w", err)
+	if !ui.Interactive() && !ui.AssumeYes() {
+		return false, &ui.MissingInputError{Inputs: []string{"--yes to accept the generated branch name without confirming"}}
+	}
+
+	issueID := normalizeIssueID(opts.IssueID)
+	if issueID == "" {
+		issueID = confirmDetectedIssue(DetectIssueID(intent))
 	}
 
-	if !ui.Confirm(fmt.Sprintf("Use branch name \"%s\"?", branchName)) {
+	currentBranch, _ := CurrentBranch()
+	if currentBranch == "HEAD" {
+		currentBranch = ""
+	}
+
+	// Settle uncommitted work first, so cancelling here costs no AI call or
+	// fetch (a new worktree starts clean)
+	dirtyChoice := ""
+	if !opts.Worktree {
+		var err error
+		dirtyChoice, err = askDirtyTree(currentBranch)
+		if err != nil {
+			return false, err
+		}
+		if dirtyChoice == dirtyCancel {
+			fmt.Println(ui.Red("❌ Branch creation cancelled."))
+			return false, nil
+		}
+	}
+
+	// AI branch naming, with collision handling and confirmation
+	choice, err := chooseBranchName(intent, issueID, opts)
+	if err != nil {
+		return false, err
+	}
+	if choice == nil {
 		fmt.Println(ui.Red("❌ Branch creation cancelled."))
-		return nil
+		return false, nil
+	}
+	branchName := choice.Name
+
+	// Start from a freshly fetched base rather than whatever HEAD is.
+	// An adopted branch already has its own history.
+	baseBranch := resolveBaseBranch(opts.BaseBranch)
+	startPoint := ""
+	if !choice.Adopt {
+		startPoint = freshStartPoint(baseBranch)
+	}
+	if startPoint == "" && baseBranch == "" {
+		baseBranch = currentBranch
 	}
 
-	// Checkout new branch
-	if err := CheckoutNewBranch(branchName); err != nil {
-		return err
+	// Deal with uncommitted work now that the target commit is known
+	var dirty dirtyPlan
+	if dirtyChoice != "" {
+		target := startPoint
+		switch {
+		case choice.Adopt && choice.RemoteOnly:
+			target = "origin/" + branchName
+		case choice.Adopt:
+			target = branchName
+		case target == "":
+			target = "HEAD"
+		}
+		dirty, err = applyDirtyChoice(dirtyChoice, currentBranch, branchName, target)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	// Save state
-	state := &RepoState{
-		ActiveTask: &ActiveTask{
-			Intent: intent,
-			Branch: branchName,
-		},
+	// Checkout new branch, either here or in a separate worktree
+	worktreeDir := ""
+	if opts.Worktree {
+		worktreeDir, err = worktreeDirFor(branchName)
+		if err != nil {
+			return false, err
+		}
+		if choice.Adopt {
+			err = AddWorktreeForBranch(branchName, worktreeDir, choice.RemoteOnly)
+		} else {
+			err = AddWorktree(branchName, worktreeDir, startPoint)
+		}
+		if err != nil {
+			return false, err
+		}
+	} else {
+		switch {
+		case choice.Adopt && choice.RemoteOnly:
+			err = CheckoutRemoteBranch(branchName)
+		case choice.Adopt:
+			err = CheckoutBranch(branchName)
+		default:
+			err = CheckoutNewBranch(branchName, startPoint)
+		}
+		if err != nil {
+			if dirty.restoreStash {
+				fmt.Println(ui.Yellow("Your changes are still stashed; restore them with: git stash pop"))
+			}
+			return false, err
+		}
 	}
-	if err := SaveState(state); err != nil {
-		return err
+
+	if dirty.restoreStash {
+		restoreStashedWork()
+	}
+
+	// Save state
+	now := time.Now()
+	err = UpdateState(func(state *RepoState) error {
+		// Adopting a branch devgod already tracks keeps its history
+		if existing := state.TaskForBranch(branchName); existing != nil && choice.Adopt {
+			existing.Intent = intent
+			if issueID != "" {
+				existing.IssueID = issueID
+			}
+			existing.touch()
+			return nil
+		}
+
+		state.AddTask(&ActiveTask{
+			Intent:     intent,
+			Branch:     branchName,
+			IssueID:    issueID,
+			BaseBranch: baseBranch,
+			Worktree:   worktreeDir,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+		return nil
+	})
+	if err != nil {
+		return false, err
 	}
 
-	fmt.Println("Created branch:", branchName)
+	switch {
+	case dryRun:
+		fmt.Println("Would switch to branch:", branchName)
+	case choice.Adopt:
+		fmt.Println("Using existing branch:", branchName)
+	default:
+		fmt.Println("Created branch:", branchName)
+	}
+	if startPoint != "" {
+		fmt.Println("Based on:", startPoint)
+	}
+	markTicketInProgress(issueID)
+	if worktreeDir != "" {
+		fmt.Println("Worktree:", worktreeDir)
+		fmt.Println("Now, run: cd", worktreeDir)
+		fmt.Println("then make your changes and run: devgod git to finish.")
+		return true, nil
+	}
 	fmt.Println("Now, make your changes and run: devgod git to finish.")
-	return nil
+	return true, nil
+}
+
+// commitContextForAI decides what to feed to AI: the full diff for small
+// changes, the name-status summary for big ones.
+func commitContextForAI(diff, summary string) string {
+	if summary == "" {
+		return diff
+	}
+
+	lines := strings.Split(summary, "\n")
+	changedFiles := 0
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			changedFiles++
+		}
+	}
+
+	// If more than 5 files changed, use the summary instead of full diff
+	if changedFiles > 5 {
+		return fmt.Sprintf(
+			"Changed files (name-status):\n%s\n",
+			strings.TrimSpace(summary),
+		)
+	}
+	return diff
+}
+
+// FinishOptions holds optional inputs for FinishTask.
+type FinishOptions struct {
+	// Split proposes several logical commits instead of one.
+	Split bool
+
+	// Amend folds staged changes into the last commit and regenerates its
+	// message from the combined diff.
+	Amend bool
+
+	// Fixup records staged changes as a fixup! commit for an earlier
+	// commit on the task branch.
+	Fixup bool
+
+	// CoAuthors asks who to credit as co-authors. The choice is kept on the
+	// task and used for its later commits.
+	CoAuthors bool
+
+	// Message is used as the commit message instead of generating one.
+	Message string
+
+	// All stages every change instead of asking which files to commit.
+	All bool
+}
+
+// missingInputs lists what FinishTask would have to ask for that can only
+// come from flags when stdin is not a terminal.
+func (opts FinishOptions) missingInputs() []string {
+	var missing []string
+	if !ui.AssumeYes() {
+		missing = append(missing, "--yes to create the commit without confirming")
+	}
+	if opts.Fixup {
+		missing = append(missing, "--fixup asks which commit to fix up; run it in a terminal")
+	}
+	if opts.CoAuthors {
+		missing = append(missing, "--co-authors asks who to credit; run it in a terminal")
+	}
+	if !opts.All && !HasStagedChanges() {
+		if files, _ := changedFiles(); len(files) > 0 {
+			missing = append(missing, "--all to commit every change, or stage files with git add first")
+		}
+	}
+	return missing
 }
 
 // FinishTask stages changes, generates commit message, and creates commit.
-func FinishTask() error {
+func FinishTask(opts FinishOptions) error {
 	if !IsGitRepo() {
 		return fmt.Errorf("not inside a git repo")
 	}
@@ -87,41 +305,52 @@ func FinishTask() error {
 		return err
 	}
 
-	if state.ActiveTask == nil {
-		return fmt.Errorf("no active task found")
+	task, err := resolveTaskForCommit(state)
+	if err != nil || task == nil {
+		return err
 	}
 
-	currentBranch, err := CurrentBranch()
-	if err == nil && currentBranch != state.ActiveTask.Branch {
-		fmt.Println(ui.Yellow("⚠️ You are NOT on the branch for this task."))
-		fmt.Println("Expected branch:", state.ActiveTask.Branch)
-		fmt.Println("Current branch: ", currentBranch)
-		fmt.Println()
-
-		if !ui.Confirm("Switch to the correct branch now?") {
-			fmt.Println(ui.Red("Commit cancelled. Switch to the correct branch and try again."))
-			return nil
+	if !ui.Interactive() {
+		if missing := opts.missingInputs(); len(missing) > 0 {
+			return &ui.MissingInputError{Inputs: missing}
 		}
+	}
 
-		if err := CheckoutBranch(state.ActiveTask.Branch); err != nil {
-			fmt.Println(ui.Red("❌ Failed to switch branches automatically."))
-			fmt.Println("Please run:")
-			fmt.Println("  git checkout", state.ActiveTask.Branch)
-			fmt.Println("and then retry.")
+	if opts.CoAuthors {
+		coAuthors, err := selectCoAuthors()
+		if err != nil {
 			return err
 		}
-
-		fmt.Println(ui.Green("✔️ Switched to the correct branch."))
-		fmt.Println()
+		task.CoAuthors = coAuthors
+		if err := UpdateTask(task.Branch, func(t *ActiveTask) {
+			t.CoAuthors = coAuthors
+			t.touch()
+		}); err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+		}
+	}
+	if len(task.CoAuthors) > 0 {
+		fmt.Println(ui.Green("👥 Co-authors:"), strings.Join(task.CoAuthors, ", "))
 	}
 
-	// Stage changes
-	if HasUnstagedChanges() {
+	if opts.All {
 		if err := StageAll(); err != nil {
 			return err
 		}
 	}
 
+	switch {
+	case opts.Amend:
+		return amendTask(task)
+	case opts.Fixup:
+		return fixupTask(task)
+	}
+
+	// Stage changes: keep a curated index, otherwise let the user pick
+	if err := prepareIndex(); err != nil {
+		return err
+	}
+
 	// Full staged diff
 	diff, err := StagedDiff()
 	if err != nil {
@@ -136,41 +365,35 @@ func FinishTask() error {
 	// Name-status summary (for counting files + preview)
 	summary, _ := StagedSummary() // ignore summary error; not fatal
 
-	// Decide what to feed to AI: full diff for small changes, summary for big ones
-	contextForAI := diff
-	if summary != "" {
-		lines := strings.Split(summary, "\n")
-		changedFiles := 0
-		for _, l := range lines {
-			if strings.TrimSpace(l) != "" {
-				changedFiles++
-			}
-		}
+	contextForAI := commitContextForAI(diff, summary)
 
-		// If more than 5 files changed, use the summary instead of full diff
-		if changedFiles > 5 {
-			contextForAI = fmt.Sprintf(
-				"Changed files (name-status):\n%s\n",
-				strings.TrimSpace(summary),
-			)
+	if opts.Split {
+		single, err := finishSplit(task, summary, contextForAI)
+		if err != nil || !single {
+			return err
 		}
 	}
 
-	// AI commit message with loading dots
-	stop := ui.StartSpinner("Letting the commit gods cook...")
-
-	// AI commit message (now based on either diff or summary)
-	commitMsg, err := ai.GenerateCommitMessage(state.ActiveTask.Intent, summary, contextForAI)
-	stop()
-	if err != nil {
-		fmt.Println(ui.Red("❌ Failed to generate commit message with AI."))
-		fmt.Println("Please complete this commit manually using git (e.g. `git commit -m \"...\"`) and then continue your flow.")
-		return err
+	commitMsg := strings.TrimSpace(opts.Message)
+	if commitMsg == "" {
+		// AI commit message with loading dots
+		stop := ui.StartSpinner("Letting the commit gods cook...")
+
+		// AI commit message (now based on either diff or summary)
+		commitMsg, err = ai.GenerateCommitMessage(task.Intent, summary, contextForAI)
+		stop()
+		if err != nil {
+			fmt.Println(ui.Red("❌ Failed to generate commit message with AI."))
+			fmt.Println("Please complete this commit manually using git (e.g. `git commit -m \"...\"`) and then continue your flow.")
+			return err
+		}
 	}
 
+	commitMsg = withTaskTrailers(commitMsg, task)
+
 	plan := ui.CommitPlan{
-		Branch:        state.ActiveTask.Branch,
-		Intent:        state.ActiveTask.Intent,
+		Branch:        task.Branch,
+		Intent:        task.Intent,
 		StagedSummary: summary,
 		CommitMessage: commitMsg,
 	}
@@ -184,12 +407,25 @@ func FinishTask() error {
 		return nil
 	}
 
-	if err := Commit(commitMsg); err != nil {
+	committed, err := commitWithRetry(commitMsg)
+	if err != nil {
 		return fmt.Errorf("commit failed: %w", err)
 	}
+	if !committed {
+		return nil
+	}
 
 	fmt.Println("✅ Commit created:")
 	fmt.Println(commitMsg)
 
+	// Record the commit on the task; failing here must not undo the commit.
+	if hash, err := HeadCommit(); err == nil {
+		err := UpdateTask(task.Branch, func(t *ActiveTask) {
+			t.recordCommit(hash, commitMsg)
+		})
+		if err != nil {
+			fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,118 @@
+package gitflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeethsoni/devgod-cli/internal/shell"
+	"github.com/jeethsoni/devgod-cli/internal/ui"
+)
+
+// worktreeDirFor returns where the worktree for a task branch lives:
+// a sibling of the main checkout, e.g. ../myrepo-feat-login-form.
+func worktreeDirFor(branch string) (string, error) {
+	common, err := GitCommonDir()
+	if err != nil {
+		return "", err
+	}
+
+	// The main checkout is the parent of the common .git dir.
+	mainRoot := filepath.Dir(common)
+	slug := strings.ReplaceAll(branch, "/", "-")
+	return filepath.Join(filepath.Dir(mainRoot), filepath.Base(mainRoot)+"-"+slug), nil
+}
+
+// AddWorktree creates a new branch checked out in its own worktree at dir,
+// starting at startPoint (or HEAD when empty).
+func AddWorktree(branch, dir, startPoint string) error {
+	args := []string{"worktree", "add", "--no-track", "-b", branch, dir}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	_, err := mutate("git", args...)
+	return err
+}
+
+// RemoveWorktree removes a linked worktree. The branch is kept.
+// It runs from the main checkout so it also works from inside dir.
+func RemoveWorktree(dir string) error {
+	common, err := GitCommonDir()
+	if err != nil {
+		return err
+	}
+	_, err = mutate("git", "-C", filepath.Dir(common), "worktree", "remove", dir)
+	return err
+}
+
+// insideDir reports whether the current directory is dir or below it.
+func insideDir(dir string) bool {
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(wd); err == nil {
+		wd = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(dir, wd)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// worktreeHasChanges reports whether the worktree at dir has uncommitted
+// or untracked files.
+func worktreeHasChanges(dir string) bool {
+	out, err := shell.Run("git", "-C", dir, "status", "--porcelain")
+	return err != nil || strings.TrimSpace(out) != ""
+}
+
+// offerWorktreeRemoval asks whether to remove the task's worktree and does
+// so on confirmation. It returns true when the worktree was removed.
+func offerWorktreeRemoval(task *ActiveTask, prompt string) bool {
+	if task.Worktree == "" {
+		return false
+	}
+	if worktreeHasChanges(task.Worktree) {
+		fmt.Println(ui.Dim("Worktree " + task.Worktree + " has uncommitted changes; keeping it."))
+		return false
+	}
+	if !ui.Offer(prompt) {
+		return false
+	}
+
+	if err := RemoveWorktree(task.Worktree); err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not remove the worktree:"), err)
+		return false
+	}
+
+	fmt.Println(ui.Green("✔️ Worktree removed:"), task.Worktree)
+	return true
+}
+
+// offerFinishedWorktreeRemoval offers, once per task, to clean up a
+// worktree task after its PR is opened. Keeping the worktree is
+// remembered; `devgod done` removes it in the end anyway.
+func offerFinishedWorktreeRemoval(task *ActiveTask) {
+	if task.Worktree == "" || task.KeepWorktree || worktreeHasChanges(task.Worktree) {
+		return
+	}
+
+	removed := offerWorktreeRemoval(task, fmt.Sprintf("PR is open. Remove the worktree %s (the branch is kept)?", task.Worktree))
+	err := UpdateTask(task.Branch, func(t *ActiveTask) {
+		if removed {
+			t.Worktree = ""
+		} else {
+			t.KeepWorktree = true
+		}
+		t.touch()
+	})
+	if err != nil {
+		fmt.Println(ui.Yellow("⚠️ Could not update devgod state:"), err)
+	}
+	if removed {
+		fmt.Println("You can leave the removed directory now, e.g. cd", filepath.Dir(task.Worktree))
+	}
+}
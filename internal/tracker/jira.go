@@ -0,0 +1,133 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Jira is a Provider backed by the Jira REST API (v2).
+type Jira struct {
+	BaseURL  string // e.g. https://acme.atlassian.net
+	Email    string
+	APIToken string
+	Client   *http.Client
+}
+
+// NewJira returns a Jira provider for the given site.
+func NewJira(baseURL, email, apiToken string) *Jira {
+	return &Jira{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Email:    email,
+		APIToken: apiToken,
+		Client:   newHTTPClient(),
+	}
+}
+
+func (j *Jira) Name() string { return "Jira" }
+
+// do sends a request to the Jira API and decodes the JSON response into out
+// (when out is non-nil).
+func (j *Jira) do(method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal jira request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, j.BaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create jira request: %w", err)
+	}
+	req.SetBasicAuth(j.Email, j.APIToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := j.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call jira: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("jira returned status %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	return nil
+}
+
+// GetIssue looks up a Jira issue by key.
+func (j *Jira) GetIssue(key string) (*Issue, error) {
+	var resp struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string `json:"summary"`
+			Description string `json:"description"`
+			IssueType   struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+		} `json:"fields"`
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=summary,description,issuetype"
+	if err := j.do(http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &Issue{
+		Key:         resp.Key,
+		Title:       resp.Fields.Summary,
+		Description: resp.Fields.Description,
+		Type:        resp.Fields.IssueType.Name,
+		URL:         j.BaseURL + "/browse/" + resp.Key,
+	}, nil
+}
+
+// Transition moves the issue through the first available transition whose
+// name or target status matches state (case-insensitive).
+func (j *Jira) Transition(key, state string) error {
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	if err := j.do(http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+
+	for _, t := range resp.Transitions {
+		if strings.EqualFold(t.To.Name, state) || strings.EqualFold(t.Name, state) {
+			req := map[string]any{"transition": map[string]string{"id": t.ID}}
+			return j.do(http.MethodPost, path, req, nil)
+		}
+	}
+	return fmt.Errorf("no transition to %q available for %s", state, key)
+}
+
+// AddComment posts a plain-text comment on the issue.
+func (j *Jira) AddComment(key, body string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/comment"
+	return j.do(http.MethodPost, path, map[string]string{"body": body}, nil)
+}
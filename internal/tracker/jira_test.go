@@ -0,0 +1,155 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newJiraServer starts a stand-in Jira site and returns a provider for it.
+func newJiraServer(t *testing.T, handler http.HandlerFunc) *Jira {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewJira(srv.URL+"/", "me@example.com", "token")
+}
+
+func TestJiraGetIssue(t *testing.T) {
+	j := newJiraServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/api/2/issue/ABC-123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "token" {
+			t.Errorf("basic auth = %q, %q, %v", user, pass, ok)
+		}
+		w.Write([]byte(`{"key":"ABC-123","fields":{"summary":"Fix login crash","description":"Empty password","issuetype":{"name":"Bug"}}}`))
+	})
+
+	issue, err := j.GetIssue("ABC-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Issue{
+		Key:         "ABC-123",
+		Title:       "Fix login crash",
+		Description: "Empty password",
+		Type:        "Bug",
+		URL:         j.BaseURL + "/browse/ABC-123",
+	}
+	if *issue != want {
+		t.Errorf("GetIssue = %+v, want %+v", *issue, want)
+	}
+}
+
+func TestJiraGetIssueErrorStatus(t *testing.T) {
+	j := newJiraServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Issue does not exist", http.StatusNotFound)
+	})
+
+	_, err := j.GetIssue("ABC-404")
+	if err == nil || !strings.Contains(err.Error(), "status 404") || !strings.Contains(err.Error(), "Issue does not exist") {
+		t.Errorf("GetIssue error = %v, want status 404 with body", err)
+	}
+}
+
+const jiraTransitions = `{"transitions":[
+	{"id":"11","name":"Start work","to":{"name":"In Progress"}},
+	{"id":"21","name":"Send to review","to":{"name":"In Review"}}
+]}`
+
+func TestJiraTransition(t *testing.T) {
+	tests := []struct {
+		state  string
+		wantID string
+	}{
+		{state: "in progress", wantID: "11"},    // by target status
+		{state: "Send to review", wantID: "21"}, // by transition name
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			var posted string
+			j := newJiraServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/rest/api/2/issue/ABC-1/transitions" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				switch r.Method {
+				case http.MethodGet:
+					w.Write([]byte(jiraTransitions))
+				case http.MethodPost:
+					var body struct {
+						Transition struct {
+							ID string `json:"id"`
+						} `json:"transition"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("decode body: %v", err)
+					}
+					posted = body.Transition.ID
+					w.WriteHeader(http.StatusNoContent)
+				}
+			})
+
+			if err := j.Transition("ABC-1", tt.state); err != nil {
+				t.Fatal(err)
+			}
+			if posted != tt.wantID {
+				t.Errorf("posted transition %q, want %q", posted, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestJiraTransitionNotFound(t *testing.T) {
+	j := newJiraServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			t.Error("no transition should be posted")
+		}
+		w.Write([]byte(jiraTransitions))
+	})
+
+	err := j.Transition("ABC-1", "Done")
+	if err == nil || !strings.Contains(err.Error(), `no transition to "Done"`) {
+		t.Errorf("Transition error = %v, want no transition", err)
+	}
+}
+
+func TestJiraTransitionErrorStatus(t *testing.T) {
+	j := newJiraServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+
+	err := j.Transition("ABC-1", "In Progress")
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Transition error = %v, want status 401", err)
+	}
+}
+
+func TestJiraAddComment(t *testing.T) {
+	var got string
+	j := newJiraServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue/ABC-1/comment" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		var body struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		got = body.Body
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1"}`))
+	})
+
+	if err := j.AddComment("ABC-1", "PR: https://github.com/o/r/pull/1"); err != nil {
+		t.Fatal(err)
+	}
+	if got != "PR: https://github.com/o/r/pull/1" {
+		t.Errorf("comment body = %q", got)
+	}
+}
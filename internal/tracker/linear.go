@@ -0,0 +1,215 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultLinearURL = "https://api.linear.app/graphql"
+
+// Linear is a Provider backed by the Linear GraphQL API.
+type Linear struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+// NewLinear returns a Linear provider. An empty apiURL uses the public API.
+func NewLinear(apiURL, apiKey string) *Linear {
+	if apiURL == "" {
+		apiURL = defaultLinearURL
+	}
+	return &Linear{
+		URL:    apiURL,
+		APIKey: apiKey,
+		Client: newHTTPClient(),
+	}
+}
+
+func (l *Linear) Name() string { return "Linear" }
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// query runs a GraphQL operation and decodes its "data" field into out.
+func (l *Linear) query(query string, vars map[string]any, out any) error {
+	data, err := json.Marshal(graphQLRequest{Query: query, Variables: vars})
+	if err != nil {
+		return fmt.Errorf("failed to marshal linear request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, l.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create linear request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", l.APIKey)
+
+	res, err := l.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call linear: %w", err)
+	}
+	defer res.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("linear returned status %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.Unmarshal(bodyBytes, &resp); err != nil {
+		return fmt.Errorf("failed to decode linear response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("linear error: %s", resp.Errors[0].Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to decode linear data: %w", err)
+	}
+	return nil
+}
+
+// mutationResult is the payload every Linear mutation returns.
+type mutationResult struct {
+	Success bool `json:"success"`
+}
+
+type linearIssue struct {
+	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Labels      struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Team struct {
+		States struct {
+			Nodes []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"nodes"`
+		} `json:"states"`
+	} `json:"team"`
+}
+
+const linearIssueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    id
+    identifier
+    title
+    description
+    url
+    labels { nodes { name } }
+    team { states { nodes { id name } } }
+  }
+}`
+
+func (l *Linear) getIssue(key string) (*linearIssue, error) {
+	var data struct {
+		Issue *linearIssue `json:"issue"`
+	}
+	if err := l.query(linearIssueQuery, map[string]any{"id": key}, &data); err != nil {
+		return nil, err
+	}
+	if data.Issue == nil {
+		return nil, fmt.Errorf("linear issue %s not found", key)
+	}
+	return data.Issue, nil
+}
+
+// GetIssue looks up a Linear issue by identifier, e.g. "ENG-123".
+func (l *Linear) GetIssue(key string) (*Issue, error) {
+	li, err := l.getIssue(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Linear has no issue types; the first label is the closest thing.
+	issueType := ""
+	if len(li.Labels.Nodes) > 0 {
+		issueType = li.Labels.Nodes[0].Name
+	}
+
+	return &Issue{
+		Key:         li.Identifier,
+		Title:       li.Title,
+		Description: li.Description,
+		Type:        issueType,
+		URL:         li.URL,
+	}, nil
+}
+
+// Transition moves the issue into the team workflow state named state.
+func (l *Linear) Transition(key, state string) error {
+	li, err := l.getIssue(key)
+	if err != nil {
+		return err
+	}
+
+	stateID := ""
+	for _, s := range li.Team.States.Nodes {
+		if strings.EqualFold(s.Name, state) {
+			stateID = s.ID
+			break
+		}
+	}
+	if stateID == "" {
+		return fmt.Errorf("no workflow state %q for %s", state, key)
+	}
+
+	const mutation = `mutation Move($id: String!, $stateId: String!) {
+  issueUpdate(id: $id, input: { stateId: $stateId }) { success }
+}`
+	var data struct {
+		IssueUpdate mutationResult `json:"issueUpdate"`
+	}
+	if err := l.query(mutation, map[string]any{"id": li.ID, "stateId": stateID}, &data); err != nil {
+		return err
+	}
+	if !data.IssueUpdate.Success {
+		return fmt.Errorf("linear did not move %s to %q", key, state)
+	}
+	return nil
+}
+
+// AddComment posts a markdown comment on the issue.
+func (l *Linear) AddComment(key, body string) error {
+	li, err := l.getIssue(key)
+	if err != nil {
+		return err
+	}
+
+	const mutation = `mutation Comment($issueId: String!, $body: String!) {
+  commentCreate(input: { issueId: $issueId, body: $body }) { success }
+}`
+	var data struct {
+		CommentCreate mutationResult `json:"commentCreate"`
+	}
+	if err := l.query(mutation, map[string]any{"issueId": li.ID, "body": body}, &data); err != nil {
+		return err
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("linear did not add the comment to %s", key)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newLinearServer starts a stand-in Linear API. handle receives each
+// decoded GraphQL request and returns the raw response body.
+func newLinearServer(t *testing.T, handle func(req graphQLRequest) string) *Linear {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "lin_key" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(handle(req)))
+	}))
+	t.Cleanup(srv.Close)
+	return NewLinear(srv.URL, "lin_key")
+}
+
+const linearIssueData = `{"data":{"issue":{
+	"id":"uuid-1","identifier":"ENG-7","title":"Add CSV export","description":"Reports page",
+	"url":"https://linear.app/acme/issue/ENG-7",
+	"labels":{"nodes":[{"name":"Feature"}]},
+	"team":{"states":{"nodes":[{"id":"s-todo","name":"Todo"},{"id":"s-prog","name":"In Progress"}]}}
+}}}`
+
+func TestLinearGetIssue(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if !strings.Contains(req.Query, "issue(id: $id)") || req.Variables["id"] != "ENG-7" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		return linearIssueData
+	})
+
+	issue, err := l.GetIssue("ENG-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Issue{
+		Key:         "ENG-7",
+		Title:       "Add CSV export",
+		Description: "Reports page",
+		Type:        "Feature",
+		URL:         "https://linear.app/acme/issue/ENG-7",
+	}
+	if *issue != want {
+		t.Errorf("GetIssue = %+v, want %+v", *issue, want)
+	}
+}
+
+func TestLinearGetIssueNotFound(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		return `{"data":{"issue":null}}`
+	})
+
+	_, err := l.GetIssue("ENG-404")
+	if err == nil || !strings.Contains(err.Error(), "ENG-404 not found") {
+		t.Errorf("GetIssue error = %v, want not found", err)
+	}
+}
+
+func TestLinearGraphQLError(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		return `{"data":null,"errors":[{"message":"Entity not found"}]}`
+	})
+
+	_, err := l.GetIssue("ENG-7")
+	if err == nil || !strings.Contains(err.Error(), "Entity not found") {
+		t.Errorf("GetIssue error = %v, want the GraphQL error", err)
+	}
+}
+
+func TestLinearErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := NewLinear(srv.URL, "bad").GetIssue("ENG-7")
+	if err == nil || !strings.Contains(err.Error(), "status 401") || !strings.Contains(err.Error(), "invalid api key") {
+		t.Errorf("GetIssue error = %v, want status 401 with body", err)
+	}
+}
+
+func TestLinearTransition(t *testing.T) {
+	var update map[string]any
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "issueUpdate") {
+			update = req.Variables
+			return `{"data":{"issueUpdate":{"success":true}}}`
+		}
+		return linearIssueData
+	})
+
+	if err := l.Transition("ENG-7", "in progress"); err != nil {
+		t.Fatal(err)
+	}
+	if update["id"] != "uuid-1" || update["stateId"] != "s-prog" {
+		t.Errorf("issueUpdate variables = %v, want id uuid-1 and stateId s-prog", update)
+	}
+}
+
+func TestLinearTransitionUnknownState(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "issueUpdate") {
+			t.Error("no update should be sent")
+		}
+		return linearIssueData
+	})
+
+	err := l.Transition("ENG-7", "In Review")
+	if err == nil || !strings.Contains(err.Error(), `no workflow state "In Review"`) {
+		t.Errorf("Transition error = %v, want unknown state", err)
+	}
+}
+
+func TestLinearTransitionGraphQLError(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "issueUpdate") {
+			return `{"errors":[{"message":"Forbidden"}]}`
+		}
+		return linearIssueData
+	})
+
+	err := l.Transition("ENG-7", "In Progress")
+	if err == nil || !strings.Contains(err.Error(), "Forbidden") {
+		t.Errorf("Transition error = %v, want the GraphQL error", err)
+	}
+}
+
+func TestLinearTransitionNotApplied(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "issueUpdate") {
+			return `{"data":{"issueUpdate":{"success":false}}}`
+		}
+		return linearIssueData
+	})
+
+	err := l.Transition("ENG-7", "In Progress")
+	if err == nil || !strings.Contains(err.Error(), "did not move ENG-7") {
+		t.Errorf("Transition error = %v, want an unapplied update", err)
+	}
+}
+
+func TestLinearAddComment(t *testing.T) {
+	var comment map[string]any
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "commentCreate") {
+			comment = req.Variables
+			return `{"data":{"commentCreate":{"success":true}}}`
+		}
+		return linearIssueData
+	})
+
+	if err := l.AddComment("ENG-7", "PR opened"); err != nil {
+		t.Fatal(err)
+	}
+	if comment["issueId"] != "uuid-1" || comment["body"] != "PR opened" {
+		t.Errorf("commentCreate variables = %v", comment)
+	}
+}
+
+func TestLinearAddCommentNotCreated(t *testing.T) {
+	l := newLinearServer(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "commentCreate") {
+			return `{"data":{"commentCreate":{"success":false}}}`
+		}
+		return linearIssueData
+	})
+
+	err := l.AddComment("ENG-7", "PR opened")
+	if err == nil || !strings.Contains(err.Error(), "did not add the comment to ENG-7") {
+		t.Errorf("AddComment error = %v, want an uncreated comment", err)
+	}
+}
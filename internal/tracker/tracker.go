@@ -0,0 +1,81 @@
+// Package tracker talks to external issue trackers (Jira, Linear) so tasks
+// can be seeded from a ticket and the ticket kept in sync with the branch.
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Default workflow states devgod moves tickets into.
+const (
+	DefaultInProgressState = "In Progress"
+	DefaultInReviewState   = "In Review"
+)
+
+// Issue is a ticket as seen by devgod.
+type Issue struct {
+	Key         string
+	Title       string
+	Description string
+	Type        string // e.g. "Bug", "Story"; used to pick the branch type
+	URL         string
+}
+
+// Provider is an issue tracker backend.
+type Provider interface {
+	// Name returns a human readable provider name, e.g. "Jira".
+	Name() string
+	// GetIssue looks up a ticket by key, e.g. "ABC-123".
+	GetIssue(key string) (*Issue, error)
+	// Transition moves a ticket into the workflow state with the given name.
+	Transition(key, state string) error
+	// AddComment posts a comment on the ticket.
+	AddComment(key, body string) error
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// FromEnv builds the provider configured through environment variables.
+// It returns (nil, nil) when no tracker is configured.
+//
+//	DEVGOD_TRACKER=jira   JIRA_BASE_URL, JIRA_EMAIL, JIRA_API_TOKEN
+//	DEVGOD_TRACKER=linear LINEAR_API_KEY, LINEAR_API_URL (optional)
+func FromEnv() (Provider, error) {
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv("DEVGOD_TRACKER")))
+
+	switch kind {
+	case "":
+		return nil, nil
+	case "jira":
+		base := os.Getenv("JIRA_BASE_URL")
+		email := os.Getenv("JIRA_EMAIL")
+		token := os.Getenv("JIRA_API_TOKEN")
+		if base == "" || email == "" || token == "" {
+			return nil, fmt.Errorf("jira tracker needs JIRA_BASE_URL, JIRA_EMAIL and JIRA_API_TOKEN")
+		}
+		return NewJira(base, email, token), nil
+	case "linear":
+		key := os.Getenv("LINEAR_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("linear tracker needs LINEAR_API_KEY")
+		}
+		return NewLinear(os.Getenv("LINEAR_API_URL"), key), nil
+	default:
+		return nil, fmt.Errorf("unknown DEVGOD_TRACKER %q; use jira or linear", kind)
+	}
+}
+
+// StateFromEnv returns the workflow state name from env var name, falling
+// back to def when it is unset.
+func StateFromEnv(name, def string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return def
+}
@@ -9,7 +9,18 @@ import (
 
 // Confirm asks the user a yes/no question in the terminal.
 // Returns true if user answers "y" or "yes" (case-insensitive).
+// Under --yes it answers yes; without a terminal it answers no and records
+// the question as missing input.
 func Confirm(prompt string) bool {
+	if assumeYes {
+		fmt.Printf("%s [y/n]: y\n", prompt)
+		return true
+	}
+	if !Interactive() {
+		_ = noInput("--yes to confirm: " + prompt)
+		return false
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Input asks the user for a single line of free text.
+// Returns the trimmed answer, which may be empty.
+func Input(prompt string) (string, error) {
+	if !Interactive() {
+		return "", noInput(prompt)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println(prompt)
+	fmt.Print("> ")
+
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// assumeYes is set by --yes: confirmations are accepted without asking.
+var assumeYes bool
+
+// unanswered collects prompts that were skipped because stdin is not a
+// terminal, so the command can report them all at the end.
+var unanswered []string
+
+// SetAssumeYes makes Confirm answer yes without reading stdin.
+func SetAssumeYes(v bool) {
+	assumeYes = v
+}
+
+// AssumeYes reports whether --yes was given.
+func AssumeYes() bool {
+	return assumeYes
+}
+
+// Interactive reports whether stdin is a terminal someone can answer
+// prompts on. Pipes, /dev/null, CI runners and editor tasks are not.
+func Interactive() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// MissingInputError reports inputs a command needed but could not ask for
+// because stdin is not a terminal.
+type MissingInputError struct {
+	Inputs []string
+}
+
+func (e *MissingInputError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("stdin is not a terminal, so these inputs must be given as flags:")
+	for _, in := range e.Inputs {
+		sb.WriteString("\n  - " + in)
+	}
+	return sb.String()
+}
+
+// Unanswered returns the prompts skipped so far because stdin is not a
+// terminal.
+func Unanswered() []string {
+	return unanswered
+}
+
+// noInput records a prompt that cannot be asked and returns the error for
+// it. The prompt is stripped of colours so the report reads cleanly.
+func noInput(prompt string) error {
+	prompt = strings.TrimSpace(stripANSI(prompt))
+	unanswered = append(unanswered, prompt)
+	fmt.Println(prompt, Dim("(skipped: no terminal)"))
+	return &MissingInputError{Inputs: []string{prompt}}
+}
+
+// stripANSI removes terminal colour sequences.
+func stripANSI(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// Offer asks an optional yes/no question, such as whether to also do some
+// extra cleanup. Unlike Confirm it is answered "no" under --yes or without
+// a terminal, so scripts only ever get the main action.
+func Offer(prompt string) bool {
+	if assumeYes || !Interactive() {
+		fmt.Printf("%s [y/n]: n\n", prompt)
+		return false
+	}
+	return Confirm(prompt)
+}
@@ -52,7 +52,13 @@ func PrintCommitPlan(plan CommitPlan) {
 	// Proposed commit message
 	fmt.Println()
 	fmt.Println("✍️  " + CommitLabelStyle.Render("Proposed commit message:"))
-	fmt.Println("   " + ValueStyle.Render(plan.CommitMessage))
+	for _, line := range strings.Split(strings.TrimRight(plan.CommitMessage, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			fmt.Println()
+			continue
+		}
+		fmt.Println("   " + ValueStyle.Render(line))
+	}
 
 	separator()
 	fmt.Println()
@@ -15,10 +15,14 @@ import (
 //	prompt: "Select reviewers..."
 //
 // User input: "1,3" -> returns ["alice", "carol"]
+// User input: "a" or "all" -> returns every item
 func SelectMultiple(items []string, prompt string) ([]string, error) {
 	if len(items) == 0 {
 		return []string{}, nil
 	}
+	if !Interactive() {
+		return nil, noInput(prompt)
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 
@@ -36,6 +40,9 @@ func SelectMultiple(items []string, prompt string) ([]string, error) {
 			// User chose nothing
 			return []string{}, nil
 		}
+		if strings.EqualFold(line, "a") || strings.EqualFold(line, "all") {
+			return append([]string{}, items...), nil
+		}
 
 		parts := strings.Split(line, ",")
 		var indices []int
@@ -78,6 +85,9 @@ func SelectOne(items []string, prompt string) (string, error) {
 	if len(items) == 0 {
 		return "", fmt.Errorf("no items to select from")
 	}
+	if !Interactive() {
+		return "", noInput(prompt)
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 